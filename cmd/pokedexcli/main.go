@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"pokedexcli/internal/cli"
+	"pokedexcli/internal/output"
+	"pokedexcli/internal/theme"
+	"strings"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: pokedexcli [flags] [command [args...]]\n\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "Without a command, starts the interactive Pokédex, or runs the\ncommands piped to stdin one per line.\n\n")
+		flag.PrintDefaults()
+	}
+	seed := flag.Uint64("seed", 0, "seed for catches and other random events, to replay a session (default random)")
+	realistic := flag.Bool("realistic", false, "only allow catching Pokémon found in the explored area")
+	script := flag.String("script", "", "run the commands in `file`, one per line (- for stdin)")
+	format := flag.String("output", "table", "print results as "+strings.Join(output.Names(), ", ")+"; json and yaml are for scripts")
+	color := flag.String("color", "auto", "when to color output: auto, always or never (auto honors NO_COLOR)")
+	themePath := flag.String("theme", "", "read colors from the theme `file` (default pokedexcli/theme.json in the config dir)")
+	flag.Parse()
+
+	formatter, err := output.New(*format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pokedexcli: %v\n", err)
+		os.Exit(2)
+	}
+	colorMode, err := theme.ParseMode(*color)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pokedexcli: %v\n", err)
+		os.Exit(2)
+	}
+
+	opts := cli.Options{
+		Realistic: *realistic,
+		Script:    *script,
+		Args:      flag.Args(),
+		Output:    formatter,
+		Color:     colorMode,
+		Theme:     *themePath,
+	}
+	// Only use the seed if it was given, 0 is a valid seed
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			opts.Seed = seed
+		}
+	})
+	if len(opts.Args) > 0 && opts.Script != "" {
+		fmt.Fprintln(os.Stderr, "pokedexcli: give either a command or --script, not both")
+		os.Exit(2)
+	}
+	os.Exit(cli.Run(opts))
+}
@@ -0,0 +1,202 @@
+// capture.go
+package catchrate
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+// Ball is a kind of Poké Ball, named as in PokeAPI ("great-ball")
+type Ball string
+
+const (
+	PokeBall    Ball = "poke-ball"
+	GreatBall   Ball = "great-ball"
+	UltraBall   Ball = "ultra-ball"
+	MasterBall  Ball = "master-ball"
+	SafariBall  Ball = "safari-ball"
+	NetBall     Ball = "net-ball"
+	NestBall    Ball = "nest-ball"
+	PremierBall Ball = "premier-ball"
+	LuxuryBall  Ball = "luxury-ball"
+	HealBall    Ball = "heal-ball"
+)
+
+// ballModifiers holds the fixed Generation III/IV catch rate multipliers;
+// balls whose bonus depends on the target are handled in Modifier
+var ballModifiers = map[Ball]float64{
+	PokeBall:    1,
+	GreatBall:   1.5,
+	UltraBall:   2,
+	MasterBall:  255,
+	SafariBall:  1.5,
+	NetBall:     1,
+	NestBall:    1,
+	PremierBall: 1,
+	LuxuryBall:  1,
+	HealBall:    1,
+}
+
+// ParseBall accepts a ball name with or without the "-ball" suffix,
+// e.g. "great" or "great-ball"
+func ParseBall(name string) (Ball, error) {
+	ball := Ball(name)
+	if _, ok := ballModifiers[ball]; ok {
+		return ball, nil
+	}
+	ball = Ball(name + "-ball")
+	if _, ok := ballModifiers[ball]; ok {
+		return ball, nil
+	}
+	return "", fmt.Errorf("unknown ball '%s'", name)
+}
+
+// Balls lists every supported ball by name
+func Balls() []Ball {
+	balls := make([]Ball, 0, len(ballModifiers))
+	for ball := range ballModifiers {
+		balls = append(balls, ball)
+	}
+	sort.Slice(balls, func(i, j int) bool { return balls[i] < balls[j] })
+	return balls
+}
+
+// Modifier returns the ball's catch rate multiplier against target
+func (b Ball) Modifier(target Target) float64 {
+	switch b {
+	case NetBall:
+		if slices.Contains(target.Types, "water") || slices.Contains(target.Types, "bug") {
+			return 3
+		}
+	case NestBall:
+		// Better against low level Pokemon; without a level it's a Poké Ball
+		if target.Level > 0 {
+			return max(1, float64(40-target.Level)/10)
+		}
+	}
+	if modifier, ok := ballModifiers[b]; ok {
+		return modifier
+	}
+	return 1
+}
+
+// Status is a non-volatile status condition of the target
+type Status string
+
+const (
+	StatusNone      Status = ""
+	StatusSleep     Status = "sleep"
+	StatusFreeze    Status = "freeze"
+	StatusParalysis Status = "paralysis"
+	StatusPoison    Status = "poison"
+	StatusBurn      Status = "burn"
+)
+
+// ParseStatus accepts a status condition name; "none" clears it
+func ParseStatus(name string) (Status, error) {
+	switch status := Status(name); status {
+	case "none":
+		return StatusNone, nil
+	case StatusNone, StatusSleep, StatusFreeze, StatusParalysis, StatusPoison, StatusBurn:
+		return status, nil
+	}
+	return "", fmt.Errorf("unknown status '%s'", name)
+}
+
+// Modifier returns the Generation III/IV status multiplier
+func (s Status) Modifier() float64 {
+	switch s {
+	case StatusSleep, StatusFreeze:
+		return 2
+	case StatusParalysis, StatusPoison, StatusBurn:
+		return 1.5
+	}
+	return 1
+}
+
+// Target describes the wild Pokemon a ball is thrown at
+type Target struct {
+	// CaptureRate is the species capture rate, 1 to 255
+	CaptureRate int
+	// Level is the Pokemon's level, or 0 if unknown
+	Level int
+	// MaxHP and CurrentHP set the HP modifier; a wild Pokemon at full HP
+	// is hardest to catch. Zero values mean full HP.
+	MaxHP     int
+	CurrentHP int
+	Status    Status
+	Types     []string
+}
+
+// Outcome is the result of throwing a ball
+type Outcome struct {
+	Caught bool
+	// Shakes is how many shake checks passed. A caught Pokemon passes all
+	// four; the ball visibly wobbles for at most three.
+	Shakes int
+	// Chance is the probability this throw had of catching the Pokemon
+	Chance float64
+}
+
+// Wobbles returns how many times the ball wobbles on screen
+func (o Outcome) Wobbles() int {
+	return min(o.Shakes, 3)
+}
+
+// shakeChecks is the number of shake checks a catch has to pass
+const shakeChecks = 4
+
+// modifiedRate returns the Generation III/IV modified catch rate "a"
+func modifiedRate(target Target, ball Ball) float64 {
+	maxHP, currentHP := float64(target.MaxHP), float64(target.CurrentHP)
+	if maxHP <= 0 {
+		maxHP, currentHP = 1, 1
+	}
+	hp := (3*maxHP - 2*currentHP) / (3 * maxHP)
+	return hp * float64(target.CaptureRate) * ball.Modifier(target) * target.Status.Modifier()
+}
+
+// shakeThreshold returns "b", the value each shake check's 16-bit random
+// number must be below
+func shakeThreshold(a float64) float64 {
+	return 1048560 / math.Sqrt(math.Sqrt(16711680/a))
+}
+
+// Chance returns the probability of catching target with ball
+func Chance(target Target, ball Ball) float64 {
+	if ball == MasterBall {
+		return 1
+	}
+	a := modifiedRate(target, ball)
+	if a >= 255 {
+		return 1
+	}
+	if a <= 0 {
+		return 0
+	}
+	return math.Pow(min(shakeThreshold(a)/65536, 1), shakeChecks)
+}
+
+// Attempt throws ball at target using the Generation III/IV capture
+// formula. intn returns a random int in [0, n) and drives the four shake
+// checks.
+func Attempt(target Target, ball Ball, intn func(n int) int) Outcome {
+	outcome := Outcome{Chance: Chance(target, ball)}
+	if outcome.Chance >= 1 {
+		outcome.Caught = true
+		outcome.Shakes = shakeChecks
+		return outcome
+	}
+
+	b := shakeThreshold(modifiedRate(target, ball))
+	for outcome.Shakes < shakeChecks {
+		if float64(intn(65536)) >= b {
+			return outcome
+		}
+		outcome.Shakes++
+	}
+	outcome.Caught = true
+	return outcome
+}
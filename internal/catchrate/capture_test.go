@@ -0,0 +1,142 @@
+package catchrate
+
+import (
+	"math"
+	"testing"
+)
+
+func TestChance(t *testing.T) {
+	cases := []struct {
+		name     string
+		target   Target
+		ball     Ball
+		expected float64
+	}{
+		{
+			// Bulbasaur at full HP in a Poké Ball is about a 6% catch
+			name:     "starter full hp",
+			target:   Target{CaptureRate: 45},
+			ball:     PokeBall,
+			expected: 0.0588,
+		},
+		{
+			name:     "starter low hp asleep in an ultra ball",
+			target:   Target{CaptureRate: 45, MaxHP: 100, CurrentHP: 1, Status: StatusSleep},
+			ball:     UltraBall,
+			expected: 0.7011,
+		},
+		{
+			name:     "easy catch",
+			target:   Target{CaptureRate: 255, MaxHP: 100, CurrentHP: 1},
+			ball:     GreatBall,
+			expected: 1,
+		},
+		{
+			name:     "master ball",
+			target:   Target{CaptureRate: 3},
+			ball:     MasterBall,
+			expected: 1,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := Chance(c.target, c.ball)
+			if math.Abs(actual-c.expected) > 0.001 {
+				t.Errorf("Chance == %.4f, expected %.4f", actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestAttemptShakeChecks(t *testing.T) {
+	target := Target{CaptureRate: 45}
+	b := shakeThreshold(modifiedRate(target, PokeBall))
+
+	cases := []struct {
+		name           string
+		rolls          []int
+		expectedShakes int
+		expectedCaught bool
+	}{
+		{name: "breaks free immediately", rolls: []int{65535}, expectedShakes: 0},
+		{name: "breaks free after two shakes", rolls: []int{0, 0, 65535}, expectedShakes: 2},
+		{name: "caught", rolls: []int{0, 0, 0, 0}, expectedShakes: 4, expectedCaught: true},
+		{name: "last check just passes", rolls: []int{0, 0, 0, int(b) - 1}, expectedShakes: 4, expectedCaught: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rolls := c.rolls
+			outcome := Attempt(target, PokeBall, func(n int) int {
+				if n != 65536 {
+					t.Fatalf("expected 16-bit shake checks, got n=%d", n)
+				}
+				roll := rolls[0]
+				rolls = rolls[1:]
+				return roll
+			})
+			if outcome.Shakes != c.expectedShakes || outcome.Caught != c.expectedCaught {
+				t.Errorf("expected %d shakes and caught=%v, got %+v", c.expectedShakes, c.expectedCaught, outcome)
+			}
+			if outcome.Wobbles() > 3 {
+				t.Errorf("expected at most 3 wobbles, got %d", outcome.Wobbles())
+			}
+		})
+	}
+}
+
+func TestAttemptGuaranteedCatch(t *testing.T) {
+	outcome := Attempt(Target{CaptureRate: 3}, MasterBall, func(n int) int {
+		t.Fatalf("a guaranteed catch shouldn't roll")
+		return 0
+	})
+	if !outcome.Caught || outcome.Wobbles() != 3 {
+		t.Errorf("expected master ball to catch after three wobbles, got %+v", outcome)
+	}
+}
+
+func TestBallModifier(t *testing.T) {
+	cases := []struct {
+		ball     Ball
+		target   Target
+		expected float64
+	}{
+		{ball: GreatBall, expected: 1.5},
+		{ball: NetBall, target: Target{Types: []string{"water"}}, expected: 3},
+		{ball: NetBall, target: Target{Types: []string{"fire"}}, expected: 1},
+		{ball: NestBall, target: Target{Level: 10}, expected: 3},
+		{ball: NestBall, target: Target{Level: 35}, expected: 1},
+		{ball: NestBall, expected: 1},
+	}
+	for _, c := range cases {
+		if actual := c.ball.Modifier(c.target); actual != c.expected {
+			t.Errorf("%s.Modifier(%+v) == %v, expected %v", c.ball, c.target, actual, c.expected)
+		}
+	}
+}
+
+func TestParseBall(t *testing.T) {
+	for _, name := range []string{"great", "great-ball"} {
+		if ball, err := ParseBall(name); err != nil || ball != GreatBall {
+			t.Errorf("ParseBall(%q) == %q, %v", name, ball, err)
+		}
+	}
+	if _, err := ParseBall("rock"); err == nil {
+		t.Errorf("expected unknown ball to fail")
+	}
+}
+
+func TestModelCaptureRate(t *testing.T) {
+	model := Model{Bounds: Bounds{Min: 100, Max: 300}, UseCaptureRate: true}
+	if actual := model.CaptureRate(300, 45); actual != 45 {
+		t.Errorf("expected species capture rate to be used, got %d", actual)
+	}
+	// Without one, the easiest Pokemon get the highest rate
+	if actual := model.CaptureRate(100, 0); actual != 255 {
+		t.Errorf("expected 255 for the easiest Pokemon, got %d", actual)
+	}
+	if actual := model.CaptureRate(300, 0); actual != 1 {
+		t.Errorf("expected 1 for the hardest Pokemon, got %d", actual)
+	}
+}
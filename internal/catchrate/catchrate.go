@@ -0,0 +1,95 @@
+// Package catchrate models how hard a Pokemon is to catch.
+package catchrate
+
+//go:generate go run ./gen -o bounds_generated.go
+
+import (
+	"context"
+	"math"
+	"pokedexcli/internal/pokeapi"
+)
+
+// maxCaptureRate is the highest species capture rate, for the easiest
+// Pokemon to catch
+const maxCaptureRate = 255
+
+// Bounds is the range of base experience that difficulty is normalised
+// over
+type Bounds struct {
+	Min int
+	Max int
+}
+
+// Model turns Pokemon data into a catch difficulty between 0 (easiest)
+// and 1 (hardest). The zero value uses DefaultBounds and ignores capture
+// rates.
+type Model struct {
+	Bounds Bounds
+	// UseCaptureRate prefers the species' capture rate over base
+	// experience when it is known
+	UseCaptureRate bool
+}
+
+// NewModel returns a model using the generated bounds and capture rates
+func NewModel() Model {
+	return Model{
+		Bounds:         DefaultBounds,
+		UseCaptureRate: true,
+	}
+}
+
+// Difficulty returns how hard a Pokemon is to catch. captureRate is the
+// species capture rate from 1 to 255, or 0 if it isn't known. Without a
+// capture rate, higher base experience means harder to catch.
+func (m Model) Difficulty(baseExperience, captureRate int) float64 {
+	if m.UseCaptureRate && captureRate > 0 {
+		return clamp(1 - float64(captureRate)/maxCaptureRate)
+	}
+
+	bounds := m.Bounds
+	if bounds.Max <= bounds.Min {
+		bounds = DefaultBounds
+	}
+	// Pokemon from generations newer than the bounds may fall outside them
+	return clamp(float64(baseExperience-bounds.Min) / float64(bounds.Max-bounds.Min))
+}
+
+// CaptureRate returns the species capture rate to use in the capture
+// formula. Without a known capture rate one is derived from difficulty.
+func (m Model) CaptureRate(baseExperience, captureRate int) int {
+	if m.UseCaptureRate && captureRate > 0 {
+		return captureRate
+	}
+	derived := int(math.Round((1 - m.Difficulty(baseExperience, 0)) * maxCaptureRate))
+	return max(1, derived)
+}
+
+// BoundsFromStats returns bounds covering every Pokemon in stats
+func BoundsFromStats(stats pokeapi.BaseExperienceStats) Bounds {
+	return Bounds{Min: stats.Min, Max: stats.Max}
+}
+
+// Calibrate computes bounds from the base experience of every species
+// PokeAPI knows about. Responses go through the client's cache, so
+// recalibrating after the first run is cheap.
+func Calibrate(ctx context.Context, client *pokeapi.Client, opts pokeapi.BulkOptions) (Bounds, int, error) {
+	count, err := client.GetPokemonSpeciesCount(ctx)
+	if err != nil {
+		return Bounds{}, 0, err
+	}
+	stats, err := client.GetBaseExperienceStats(ctx, count, opts)
+	if err != nil {
+		return Bounds{}, 0, err
+	}
+	return BoundsFromStats(stats), count, nil
+}
+
+func clamp(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,77 @@
+package catchrate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"pokedexcli/internal/pokeapi"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDifficulty(t *testing.T) {
+	model := Model{Bounds: Bounds{Min: 100, Max: 300}, UseCaptureRate: true}
+	cases := []struct {
+		name           string
+		baseExperience int
+		captureRate    int
+		expected       float64
+	}{
+		{name: "lowest base experience", baseExperience: 100, expected: 0},
+		{name: "middle base experience", baseExperience: 200, expected: 0.5},
+		{name: "above bounds is clamped", baseExperience: 400, expected: 1},
+		{name: "below bounds is clamped", baseExperience: 50, expected: 0},
+		{name: "capture rate wins", baseExperience: 300, captureRate: 255, expected: 0},
+		{name: "legendary capture rate", baseExperience: 100, captureRate: 3, expected: 1 - 3.0/255},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := model.Difficulty(c.baseExperience, c.captureRate)
+			if actual != c.expected {
+				t.Errorf("Difficulty(%d, %d) == %v, expected %v", c.baseExperience, c.captureRate, actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestZeroModel(t *testing.T) {
+	// The zero value ignores capture rates and uses the generated bounds
+	model := Model{}
+	if actual := model.Difficulty(DefaultBounds.Max, 255); actual != 1 {
+		t.Errorf("expected max base experience to be hardest, got %v", actual)
+	}
+	if actual := model.Difficulty(DefaultBounds.Min, 0); actual != 0 {
+		t.Errorf("expected min base experience to be easiest, got %v", actual)
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pokemon-species" {
+			w.Write([]byte(`{"count": 4}`))
+			return
+		}
+		id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/pokemon/"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"id": %d, "base_experience": %d}`, id, 50+id*10)
+	}))
+	defer server.Close()
+
+	client := pokeapi.NewClient(pokeapi.WithBaseURL(server.URL), pokeapi.WithRateLimit(0, 0))
+	bounds, species, err := Calibrate(context.Background(), client, pokeapi.BulkOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if species != 4 {
+		t.Errorf("expected 4 species, got %d", species)
+	}
+	if bounds != (Bounds{Min: 60, Max: 90}) {
+		t.Errorf("unexpected bounds: %+v", bounds)
+	}
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// parseFlags splits args into positional arguments and "--name value" or
+// "--name=value" flags. Only the given flag names are accepted.
+func parseFlags(args []string, names ...string) ([]string, map[string]string, error) {
+	positional := []string{}
+	flags := map[string]string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if !slices.Contains(names, name) {
+			return nil, nil, fmt.Errorf("unknown flag '--%s'", name)
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("flag '--%s' needs a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		flags[name] = value
+	}
+	return positional, flags, nil
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	cases := []struct {
+		args               []string
+		expectedPositional []string
+		expectedFlags      map[string]string
+		expectErr          bool
+	}{
+		{
+			args:               []string{"pikachu", "sparky"},
+			expectedPositional: []string{"pikachu", "sparky"},
+			expectedFlags:      map[string]string{},
+		},
+		{
+			args:               []string{"pikachu", "--ball", "great", "sparky"},
+			expectedPositional: []string{"pikachu", "sparky"},
+			expectedFlags:      map[string]string{"ball": "great"},
+		},
+		{
+			args:               []string{"--ball=ultra", "pikachu"},
+			expectedPositional: []string{"pikachu"},
+			expectedFlags:      map[string]string{"ball": "ultra"},
+		},
+		{
+			args:      []string{"pikachu", "--ball"},
+			expectErr: true,
+		},
+		{
+			args:      []string{"pikachu", "--bait", "yes"},
+			expectErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		positional, flags, err := parseFlags(c.args, "ball")
+		if c.expectErr {
+			if err == nil {
+				t.Errorf("parseFlags(%q) expected an error", c.args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseFlags(%q) unexpected error: %v", c.args, err)
+			continue
+		}
+		if !reflect.DeepEqual(positional, c.expectedPositional) || !reflect.DeepEqual(flags, c.expectedFlags) {
+			t.Errorf("parseFlags(%q) == %q, %v, expected %q, %v", c.args, positional, flags, c.expectedPositional, c.expectedFlags)
+		}
+	}
+}
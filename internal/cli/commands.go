@@ -1,10 +1,20 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"math/rand/v2"
 	"os"
+	"pokedexcli/internal/catchrate"
+	"pokedexcli/internal/encounter"
 	"pokedexcli/internal/models"
+	"pokedexcli/internal/output"
+	"pokedexcli/internal/pokeapi"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -13,7 +23,19 @@ import (
 type Command struct {
 	Name        string
 	Description string
-	Callback    func(*models.ReplConfig, []string) error
+	Callback    func(context.Context, Streams, *models.ReplConfig, []string) error
+}
+
+// Streams are where a command writes its results and its errors, so
+// commands can run outside a terminal and be tested
+type Streams struct {
+	Out io.Writer
+	Err io.Writer
+}
+
+// StdStreams writes to the process's stdout and stderr
+func StdStreams() Streams {
+	return Streams{Out: os.Stdout, Err: os.Stderr}
 }
 
 // CommandsMap holds all available commands
@@ -32,23 +54,48 @@ func init() {
 			Callback:    CommandHelp,
 		},
 		"map": {
-			Name:        "map",
-			Description: "Display the next 20 locations",
+			Name:        "map [first|last]",
+			Description: "Display the next locations [--page <n>] [--limit <n>]",
 			Callback:    CommandMap,
 		},
 		"mapb": {
 			Name:        "mapb",
-			Description: "Display the previous 20 locations",
+			Description: "Display the previous locations",
 			Callback:    CommandMapb,
 		},
+		"regions": {
+			Name:        "regions",
+			Description: "List all regions",
+			Callback:    CommandRegions,
+		},
+		"region": {
+			Name:        "region <region_name>",
+			Description: "List the locations in a region",
+			Callback:    CommandRegion,
+		},
+		"location": {
+			Name:        "location <location_name>",
+			Description: "List the areas in a location",
+			Callback:    CommandLocation,
+		},
 		"explore": {
 			Name:        "explore <area_name>",
 			Description: "List all Pokémon in a specific area",
 			Callback:    CommandExplore,
 		},
+		"walk": {
+			Name:        "walk [method]",
+			Description: "Take a step in the current area to find wild Pokémon",
+			Callback:    CommandWalk,
+		},
+		"flee": {
+			Name:        "flee",
+			Description: "Run away from a wild Pokémon",
+			Callback:    CommandFlee,
+		},
 		"catch": {
-			Name:        "catch <pokemon_name>",
-			Description: "Attempt to catch a Pokémon",
+			Name:        "catch [pokemon_name] [nickname] [--ball <ball>] [--hp <percent>] [--status <status>]",
+			Description: "Attempt to catch a Pokémon, or the wild one met on a walk",
 			Callback:    CommandCatch,
 		},
 		"inspect": {
@@ -61,190 +108,460 @@ func init() {
 			Description: "List all caught Pokémon",
 			Callback:    CommandPokedex,
 		},
+		"mode": {
+			Name:        "mode [free|realistic]",
+			Description: "Show or set whether catches are limited to the current area",
+			Callback:    CommandMode,
+		},
+		"version": {
+			Name:        "version [name|any]",
+			Description: "Show or set the game version for encounters and sprites",
+			Callback:    CommandVersion,
+		},
+		"seed": {
+			Name:        "seed [number|random]",
+			Description: "Show or set the random seed for catches",
+			Callback:    CommandSeed,
+		},
+		"cache": {
+			Name:        "cache [stats|clear|list]",
+			Description: "Show or manage cached API responses",
+			Callback:    CommandCache,
+		},
 	}
 }
 
-func CommandMap(config *models.ReplConfig, args []string) error {
-	locationAreasListResponse, err := config.PokeApiClient.GetLocationAreasList(config.Next, args)
+func CommandMap(ctx context.Context, streams Streams, config *models.ReplConfig, args []string) error {
+	request, err := parseMapArgs(args)
 	if err != nil {
 		return err
 	}
+	if request.limit > 0 {
+		config.PageSize = request.limit
+	}
+	limit := pageSize(config)
 
-	fmt.Printf("%s═══ Locations ═══%s\n", colorCyan, colorReset)
-	for i, response := range locationAreasListResponse.Results {
-		fmt.Printf("%s%2d.%s %s\n", colorGray, i+1, colorReset, response.Name)
+	// Without a jump, carry on from the cursor like before
+	opts := pokeapi.ListOptions{Limit: limit}
+	if config.Next != "" {
+		if opts, err = pokeapi.ParseListOptions(config.Next); err != nil {
+			return err
+		}
+		// A new page size starts from where the cursor was
+		if request.limit > 0 {
+			opts.Limit = limit
+		}
 	}
 
-	// Update config with next/previous URLs for pagination
-	config.Next = locationAreasListResponse.Next
-	config.Previous = locationAreasListResponse.Previous
+	page := request.page
+	if request.last {
+		// The last page depends on how many areas there are
+		countResponse, err := config.PokeApiClient.ListLocationAreas(ctx, pokeapi.ListOptions{Limit: 1})
+		if err != nil {
+			return apiError(err, "locations", "")
+		}
+		page = max(1, pokeapi.ListOptions{Limit: limit}.Pages(countResponse.Count))
+	}
+	if page > 0 {
+		opts = pokeapi.ListOptions{Offset: (page - 1) * limit, Limit: limit}
+	}
 
+	locationAreasListResponse, err := config.PokeApiClient.ListLocationAreas(ctx, opts)
+	if err != nil {
+		return apiError(err, "locations", "")
+	}
+	if len(locationAreasListResponse.Results) == 0 && opts.Page() > 1 {
+		return fmt.Errorf("page %d is past the last page (%d)", opts.Page(), opts.Pages(locationAreasListResponse.Count))
+	}
+
+	result := locationPage(config, opts, locationAreasListResponse)
 	if config.Next != "" {
-		fmt.Printf("\n%sType 'map' for more locations%s\n", colorGray, colorReset)
+		result.hint = "Type 'map' for more locations"
 	}
-	return nil
+	return render(streams, config, result)
 }
 
-func CommandMapb(config *models.ReplConfig, args []string) error {
+func CommandMapb(ctx context.Context, streams Streams, config *models.ReplConfig, args []string) error {
 	if config.Previous == "" {
-		fmt.Printf("%s⚠ You're on the first page%s\n", colorYellow, colorReset)
 		config.Next = ""
-		return nil
+		return render(streams, config, Notice{Message: "You're on the first page", warning: true})
 	}
 
-	locationAreasListResponse, err := config.PokeApiClient.GetLocationAreasList(config.Previous, args)
+	opts, err := pokeapi.ParseListOptions(config.Previous)
 	if err != nil {
 		return err
 	}
+	locationAreasListResponse, err := config.PokeApiClient.ListLocationAreas(ctx, opts)
+	if err != nil {
+		return apiError(err, "locations", "")
+	}
+
+	result := locationPage(config, opts, locationAreasListResponse)
+	if config.Previous != "" {
+		result.hint = "Type 'mapb' for previous locations"
+	}
+	return render(streams, config, result)
+}
+
+// maxPageSize is the largest page map will ask for
+const maxPageSize = 100
+
+// mapRequest is the page the map arguments ask for
+type mapRequest struct {
+	// page is the page to jump to, or 0 to follow the cursor
+	page int
+	last bool
+	// limit is the new page size, or 0 to keep the current one
+	limit int
+}
+
+// parseMapArgs parses "map [first|last] [--page <n>] [--limit <n>]"
+func parseMapArgs(args []string) (mapRequest, error) {
+	const usage = "usage: map [first|last] [--page <n>] [--limit <n>]"
+	positional, flags, err := parseFlags(args, "page", "limit")
+	if err != nil {
+		return mapRequest{}, fmt.Errorf("%w\n%s", err, usage)
+	}
+
+	request := mapRequest{}
+	if value, ok := flags["limit"]; ok {
+		if request.limit, err = strconv.Atoi(value); err != nil || request.limit < 1 || request.limit > maxPageSize {
+			return mapRequest{}, fmt.Errorf("--limit must be a number from 1 to %d", maxPageSize)
+		}
+	}
+	if value, ok := flags["page"]; ok {
+		if request.page, err = strconv.Atoi(value); err != nil || request.page < 1 {
+			return mapRequest{}, fmt.Errorf("--page must be a number from 1")
+		}
+	}
+
+	switch {
+	case len(positional) == 0:
+	case len(positional) > 1 || request.page > 0:
+		return mapRequest{}, fmt.Errorf("%s", usage)
+	case positional[0] == "first":
+		request.page = 1
+	case positional[0] == "last":
+		request.last = true
+	default:
+		return mapRequest{}, fmt.Errorf("%s", usage)
+	}
+	return request, nil
+}
+
+// pageSize returns the number of locations map shows at a time
+func pageSize(config *models.ReplConfig) int {
+	if config.PageSize > 0 {
+		return config.PageSize
+	}
+	return pokeapi.DefaultPageSize
+}
 
-	fmt.Printf("%s═══ Locations ═══%s\n", colorCyan, colorReset)
-	for i, location := range locationAreasListResponse.Results {
-		fmt.Printf("%s%2d.%s %s\n", colorGray, i+1, colorReset, location.Name)
+// locationPage builds the page of locations opts asked for and moves the
+// map cursor to it
+func locationPage(config *models.ReplConfig, opts pokeapi.ListOptions, locationAreasListResponse pokeapi.LocationAreasListResponse) LocationPage {
+	result := LocationPage{
+		Page:      opts.Page(),
+		Pages:     opts.Pages(locationAreasListResponse.Count),
+		Count:     locationAreasListResponse.Count,
+		Locations: []string{},
+	}
+	for _, location := range locationAreasListResponse.Results {
+		result.Locations = append(result.Locations, location.Name)
 	}
 
 	// Update config with next/previous URLs for pagination
 	config.Next = locationAreasListResponse.Next
 	config.Previous = locationAreasListResponse.Previous
+	return result
+}
 
-	if config.Previous != "" {
-		fmt.Printf("\n%sType 'mapb' for previous locations%s\n", colorGray, colorReset)
+func CommandRegions(ctx context.Context, streams Streams, config *models.ReplConfig, args []string) error {
+	regionsListResponse, err := config.PokeApiClient.GetRegionsList(ctx)
+	if err != nil {
+		return apiError(err, "regions", "")
 	}
-	return nil
+
+	result := RegionList{Regions: []string{}}
+	for _, region := range regionsListResponse.Results {
+		result.Regions = append(result.Regions, region.Name)
+	}
+	return render(streams, config, result)
 }
 
-func CommandExplore(config *models.ReplConfig, args []string) error {
+func CommandRegion(ctx context.Context, streams Streams, config *models.ReplConfig, args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("usage: explore <area_name>")
+		return fmt.Errorf("usage: region <region_name>")
 	}
 
-	areaName := args[0]
-	fmt.Printf("%sExploring %s...%s\n", colorYellow, areaName, colorReset)
-
-	locationAreasDetailsResponse, err := config.PokeApiClient.GetLocationAreasDetail(areaName)
+	regionName := args[0]
+	regionResponse, err := config.PokeApiClient.GetRegion(ctx, regionName)
 	if err != nil {
-		return err
+		return apiError(err, "region", regionName)
 	}
 
-	if len(locationAreasDetailsResponse.PokemonEncounters) == 0 {
-		fmt.Printf("%sNo Pokémon found in this area%s\n", colorGray, colorReset)
-		return nil
+	result := RegionDetails{
+		Name:       regionResponse.Name,
+		Generation: regionResponse.MainGeneration.Name,
+		Locations:  []string{},
+	}
+	for _, location := range regionResponse.Locations {
+		result.Locations = append(result.Locations, location.Name)
 	}
+	return render(streams, config, result)
+}
 
-	fmt.Printf("\n%s═══ Pokémon Found in %s ═══%s\n", colorGreen, areaName, colorReset)
-	for i, pokemonEncounter := range locationAreasDetailsResponse.PokemonEncounters {
-		fmt.Printf("%s%2d.%s %s\n", colorGray, i+1, colorReset, pokemonEncounter.Pokemon.Name)
+func CommandLocation(ctx context.Context, streams Streams, config *models.ReplConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: location <location_name>")
 	}
-	fmt.Printf("\n%sUse 'catch <pokemon_name>' to attempt a catch!%s\n", colorGray, colorReset)
 
-	return nil
+	locationName := args[0]
+	locationResponse, err := config.PokeApiClient.GetLocation(ctx, locationName)
+	if err != nil {
+		return apiError(err, "location", locationName)
+	}
+
+	result := LocationDetails{
+		Name:   locationResponse.Name,
+		Region: locationResponse.Region.Name,
+		Areas:  []string{},
+	}
+	for _, area := range locationResponse.Areas {
+		result.Areas = append(result.Areas, area.Name)
+	}
+	return render(streams, config, result)
 }
 
-func CommandCatch(config *models.ReplConfig, args []string) error {
+func CommandExplore(ctx context.Context, streams Streams, config *models.ReplConfig, args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("usage: catch <pokemon_name>")
+		return fmt.Errorf("usage: explore <area_name>")
 	}
 
-	pokemonName := args[0]
+	areaName := args[0]
+	progress(streams, config, "%sExploring %s...%s\n", colorYellow, areaName, colorReset)
 
-	// Check if already caught
-	if _, exists := config.Pokedex[pokemonName]; exists {
-		fmt.Printf("%s✓ You've already caught %s!%s\n", colorYellow, pokemonName, colorReset)
-		return nil
+	locationAreasDetailsResponse, err := config.PokeApiClient.GetLocationAreasDetail(ctx, areaName)
+	if err != nil {
+		return apiError(err, "area", areaName)
+	}
+
+	// Remember where we are so catches can record it, and what lives here
+	// for realistic mode
+	config.CurrentArea = locationAreasDetailsResponse.Name
+	config.Encounters = encounter.NewTable(locationAreasDetailsResponse)
+	config.Wild = nil
+
+	result := AreaDetails{
+		Area:     areaName,
+		Version:  config.Version,
+		Pokemon:  []AreaPokemon{},
+		detailed: config.Realistic || config.Version != "",
+	}
+	for _, name := range config.Encounters.Pokemon(config.Version) {
+		pokemon := AreaPokemon{Name: name, Methods: []string{}}
+		if summary, ok := config.Encounters.Summarize(name, config.Version); ok {
+			pokemon.Chance = summary.Chance
+			pokemon.MinLevel = summary.MinLevel
+			pokemon.MaxLevel = summary.MaxLevel
+			pokemon.Methods = summary.Methods
+		}
+		result.Pokemon = append(result.Pokemon, pokemon)
 	}
+	return render(streams, config, result)
+}
 
-	pokemonResponse, err := config.PokeApiClient.GetPokemonInformation(pokemonName)
+func CommandCatch(ctx context.Context, streams Streams, config *models.ReplConfig, args []string) error {
+	const usage = "usage: catch <pokemon_name> [nickname] [--ball <ball>] [--hp <percent>] [--status <status>]"
+	positional, flags, err := parseFlags(args, "ball", "hp", "status")
 	if err != nil {
-		return err
+		return fmt.Errorf("%w\n%s", err, usage)
+	}
+	// A wild Pokémon from walk can be caught without naming it
+	if len(positional) == 0 && config.Wild != nil {
+		positional = []string{config.Wild.Pokemon}
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("%s", usage)
+	}
+
+	pokemonName := positional[0]
+	nickname := ""
+	if len(positional) > 1 {
+		nickname = positional[1]
+	}
+
+	ball := catchrate.PokeBall
+	if name, ok := flags["ball"]; ok {
+		if ball, err = catchrate.ParseBall(name); err != nil {
+			return fmt.Errorf("%w, try one of: %s", err, ballNames())
+		}
 	}
 
-	// Higher base experience = harder to catch (inverted from before)
-	// Normalize between 0 (easiest) and 1 (hardest)
-	catchDifficulty := ((float32(pokemonResponse.BaseExperience) - 36.00) / (608.00 - 36.00))
-	roll := rand.Float32()
+	target := catchrate.Target{}
+	if percent, ok := flags["hp"]; ok {
+		hp, err := strconv.Atoi(strings.TrimSuffix(percent, "%"))
+		if err != nil || hp < 1 || hp > 100 {
+			return fmt.Errorf("--hp must be a percentage from 1 to 100")
+		}
+		target.MaxHP, target.CurrentHP = 100, hp
+	}
+	if name, ok := flags["status"]; ok {
+		if target.Status, err = catchrate.ParseStatus(name); err != nil {
+			return fmt.Errorf("%w, try one of: sleep, freeze, paralysis, poison, burn", err)
+		}
+	}
 
-	fmt.Printf("%sThrowing a Pokéball at %s...%s\n", colorYellow, pokemonName, colorReset)
+	// Check if already caught. A wild one from walk that isn't worth a
+	// ball is left behind rather than blocking the way.
+	if _, exists := config.Pokedex[pokemonName]; exists {
+		if config.Wild != nil && config.Wild.Pokemon == pokemonName {
+			config.Wild = nil
+		}
+		return render(streams, config, CatchResult{Pokemon: pokemonName, Outcome: outcomeAlreadyCaught})
+	}
 
-	// Simulate 3 shakes with suspenseful pauses
-	shakes := []string{"Wobble...", "Wobble...", "Wobble..."}
+	// A Pokémon met on a walk is already here. In realistic mode any other
+	// Pokémon has to live here, and show up.
+	if config.Wild != nil && config.Wild.Pokemon == pokemonName {
+		target.Level = config.Wild.Level
+	} else if config.Realistic {
+		if config.CurrentArea == "" {
+			return fmt.Errorf("explore an area first, realistic mode only allows catching Pokémon found there")
+		}
+		if !config.Encounters.Has(pokemonName, config.Version) {
+			return fmt.Errorf("there are no wild %s in %s", pokemonName, config.CurrentArea)
+		}
+		wild, found := config.Encounters.Search(pokemonName, config.Version, config.Rand.IntN)
+		if !found {
+			return render(streams, config, CatchResult{Pokemon: pokemonName, Outcome: outcomeNotFound, Area: config.CurrentArea})
+		}
+		progress(streams, config, "%sA wild %s (Lv. %d) appeared!%s\n", colorGreen, pokemonName, wild.Level, colorReset)
+		target.Level = wild.Level
+	}
 
-	for i, shake := range shakes {
-		time.Sleep(800 * time.Millisecond)
-		fmt.Print(shake)
+	pokemonResponse, err := config.PokeApiClient.GetPokemonInformation(ctx, pokemonName)
+	if err != nil {
+		return apiError(err, "Pokémon", pokemonName)
+	}
 
-		// For dramatic effect, check if Pokemon breaks free after each shake
-		// Each shake has increasing chance to succeed
-		shakeThreshold := catchDifficulty * float32(3-i) / 3.0
+	// The species capture rate is the most accurate measure of difficulty;
+	// without it the model derives one from base experience
+	speciesCaptureRate := 0
+	if config.CatchModel.UseCaptureRate {
+		speciesResponse, err := config.PokeApiClient.GetPokemonSpecies(ctx, pokemonResponse.Species.Name)
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
+		if err == nil {
+			speciesCaptureRate = speciesResponse.CaptureRate
+		}
+	}
 
-		if roll > shakeThreshold {
-			// Pokemon breaks free
-			if i < 2 { // Only break free on first two shakes for drama
-				time.Sleep(500 * time.Millisecond)
-				fmt.Print(" ")
+	pokemon := models.NewPokemon(pokemonResponse)
+	pokemon.Sprite = pokemonResponse.Sprite(config.Version)
+	target.CaptureRate = config.CatchModel.CaptureRate(pokemonResponse.BaseExperience, speciesCaptureRate)
+	target.Types = pokemon.Types
+	outcome := catchrate.Attempt(target, ball, config.Rand.IntN)
+
+	// Wobble once for every shake check the ball passed; the animation is
+	// only for people watching
+	if output.IsTable(config.Output) {
+		fmt.Fprintf(streams.Out, "%sThrowing %s at %s...%s\n", colorYellow, ballDisplayName(string(ball)), pokemonName, colorReset)
+		if err := config.Clock.Sleep(ctx, 800*time.Millisecond); err != nil {
+			return err
+		}
+		for i := 0; i < outcome.Wobbles(); i++ {
+			fmt.Fprint(streams.Out, "Wobble... ")
+			if err := config.Clock.Sleep(ctx, 800*time.Millisecond); err != nil {
+				return err
 			}
-		} else {
-			// Will succeed
-			time.Sleep(500 * time.Millisecond)
-			fmt.Print(" ")
+		}
+		if outcome.Wobbles() > 0 {
+			fmt.Fprintln(streams.Out)
 		}
 	}
-	fmt.Println()
 
-	// Final result check: easier Pokemon = higher success chance
-	if roll > catchDifficulty {
-		fmt.Printf("%s✓ Gotcha! %s was caught!%s\n", colorGreen, pokemonName, colorReset)
-		fmt.Printf("  %sBase Experience: %d%s\n", colorGray, pokemonResponse.BaseExperience, colorReset)
-		config.Pokedex[pokemonResponse.Name] = models.Pokemon{Name: pokemonResponse.Name}
-	} else {
-		fmt.Printf("%s✗ Oh no! %s broke free!%s\n", colorRed, pokemonName, colorReset)
-		catchRate := (1.0 - catchDifficulty) * 100
-		fmt.Printf("  %sCatch rate: %.1f%% - Try again!%s\n", colorGray, catchRate, colorReset)
+	result := CatchResult{
+		Pokemon:        pokemonName,
+		Outcome:        outcomeBrokeFree,
+		Area:           config.CurrentArea,
+		Ball:           string(ball),
+		Level:          target.Level,
+		Shakes:         outcome.Wobbles(),
+		Chance:         outcome.Chance,
+		BaseExperience: pokemonResponse.BaseExperience,
 	}
-	return nil
+	if outcome.Caught {
+		result.Outcome = outcomeCaught
+		pokemon.Nickname = nickname
+		pokemon.CaughtAt = config.Clock.Now()
+		pokemon.CaughtIn = config.CurrentArea
+		pokemon.Level = target.Level
+		pokemon.Ball = string(ball)
+		pokemon.CatchChance = outcome.Chance
+		config.Pokedex[pokemonResponse.Name] = pokemon
+		if config.Wild != nil && config.Wild.Pokemon == pokemonName {
+			config.Wild = nil
+		}
+		if err := saveSession(config); err != nil {
+			return err
+		}
+	}
+	return render(streams, config, result)
 }
 
-func CommandInspect(config *models.ReplConfig, args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("usage: inspect <pokemon_name>")
+// ballDisplayName turns "ultra-ball" into "an ultra ball"
+func ballDisplayName(ball string) string {
+	name := strings.ReplaceAll(ball, "-", " ")
+	if strings.ContainsRune("aeiou", rune(name[0])) {
+		return "an " + name
 	}
+	return "a " + name
+}
 
-	pokemonName := args[0]
-	_, pokemonExists := config.Pokedex[pokemonName]
-	if !pokemonExists {
-		fmt.Printf("%s✗ You haven't caught %s yet!%s\n", colorRed, pokemonName, colorReset)
-		fmt.Printf("  %sUse 'catch %s' to attempt a catch%s\n", colorGray, pokemonName, colorReset)
-		return nil
+// ballNames lists the supported balls without their "-ball" suffix
+func ballNames() string {
+	names := []string{}
+	for _, ball := range catchrate.Balls() {
+		names = append(names, strings.TrimSuffix(string(ball), "-ball"))
 	}
+	return strings.Join(names, ", ")
+}
 
-	pokemonResponse, err := config.PokeApiClient.GetPokemonInformation(pokemonName)
-	if err != nil {
-		return fmt.Errorf("failed to get info for pokemon %s", pokemonName)
+func CommandInspect(ctx context.Context, streams Streams, config *models.ReplConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: inspect <pokemon_name>")
 	}
 
-	// Print header
-	fmt.Printf("\n%s╔═══════════════════════════════════╗%s\n", colorCyan, colorReset)
-	fmt.Printf("%s║  %-31s  ║%s\n", colorCyan, strings.ToUpper(pokemonResponse.Name), colorReset)
-	fmt.Printf("%s╚═══════════════════════════════════╝%s\n\n", colorCyan, colorReset)
-
-	// Basic info
-	fmt.Printf("%sHeight:%s %d decimetres\n", colorBold, colorReset, pokemonResponse.Height)
-	fmt.Printf("%sWeight:%s %d hectograms\n\n", colorBold, colorReset, pokemonResponse.Weight)
-
-	// Types
-	fmt.Printf("%sTypes:%s\n", colorBold, colorReset)
-	for _, t := range pokemonResponse.Types {
-		typeColor := getTypeColor(t.Type.Name)
-		fmt.Printf("  • %s%s%s\n", typeColor, t.Type.Name, colorReset)
+	pokemonName := args[0]
+	pokemon, pokemonExists := config.Pokedex[pokemonName]
+	if !pokemonExists {
+		return fmt.Errorf("you haven't caught %s yet, use 'catch %s' to attempt a catch", pokemonName, pokemonName)
 	}
 
-	// Stats with visual bars
-	fmt.Printf("\n%sStats:%s\n", colorBold, colorReset)
-	for _, s := range pokemonResponse.Stats {
-		statName := strings.ReplaceAll(s.Stat.Name, "-", " ")
-		bar := generateStatBar(s.BaseStat)
-		fmt.Printf("  %-18s %s%3d%s %s\n", statName+":", colorGray, s.BaseStat, colorReset, bar)
+	// Records from older saves only hold a name; fill them in once
+	if !pokemon.HasDetails() {
+		pokemonResponse, err := config.PokeApiClient.GetPokemonInformation(ctx, pokemonName)
+		if err != nil {
+			return apiError(err, "Pokémon", pokemonName)
+		}
+		details := models.NewPokemon(pokemonResponse)
+		details.Sprite = pokemonResponse.Sprite(config.Version)
+		details.Nickname = pokemon.Nickname
+		details.CaughtAt = pokemon.CaughtAt
+		details.CaughtIn = pokemon.CaughtIn
+		details.Level = pokemon.Level
+		details.Ball = pokemon.Ball
+		details.CatchChance = pokemon.CatchChance
+		pokemon = details
+		config.Pokedex[pokemonName] = pokemon
+		if err := saveSession(config); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return render(streams, config, PokemonDetails{Pokemon: pokemon})
 }
 
 // generateStatBar creates a visual bar for stats
@@ -269,80 +586,223 @@ func generateStatBar(stat int) string {
 
 // getTypeColor returns appropriate color for Pokemon type
 func getTypeColor(typeName string) string {
-	typeColors := map[string]string{
-		"fire":     colorRed,
-		"water":    colorBlue,
-		"grass":    colorGreen,
-		"electric": colorYellow,
-		"psychic":  colorPurple,
-		"ice":      colorCyan,
-		"dragon":   colorPurple,
-		"dark":     colorGray,
-		"fairy":    colorPurple,
-	}
-
 	if color, exists := typeColors[typeName]; exists {
 		return color
 	}
 	return colorReset
 }
 
-func CommandPokedex(config *models.ReplConfig, args []string) error {
-	if len(config.Pokedex) == 0 {
-		fmt.Printf("%sYour Pokédex is empty!%s\n", colorYellow, colorReset)
-		fmt.Printf("  %sUse 'explore' and 'catch' to start collecting Pokémon%s\n", colorGray, colorReset)
-		return nil
+func CommandPokedex(ctx context.Context, streams Streams, config *models.ReplConfig, args []string) error {
+	names := make([]string, 0, len(config.Pokedex))
+	for name := range config.Pokedex {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	fmt.Printf("%s╔═══════════════════════════════════╗%s\n", colorGreen, colorReset)
-	fmt.Printf("%s║         YOUR POKÉDEX (%3d)        ║%s\n", colorGreen, len(config.Pokedex), colorReset)
-	fmt.Printf("%s╚═══════════════════════════════════╝%s\n\n", colorGreen, colorReset)
+	result := PokedexList{Count: len(names), Pokemon: []models.Pokemon{}}
+	for _, name := range names {
+		result.Pokemon = append(result.Pokemon, config.Pokedex[name])
+	}
+	return render(streams, config, result)
+}
 
-	i := 1
-	for _, pokemon := range config.Pokedex {
-		fmt.Printf("%s%2d.%s %s\n", colorGray, i, colorReset, pokemon.Name)
-		i++
+func CommandExit(ctx context.Context, streams Streams, config *models.ReplConfig, args []string) error {
+	if err := render(streams, config, Goodbye{Caught: len(config.Pokedex)}); err != nil {
+		printError(streams.Err, err)
+	}
+	if err := saveSession(config); err != nil {
+		printError(streams.Err, err)
 	}
+	return ErrExit
+}
 
-	fmt.Printf("\n%sUse 'inspect <pokemon_name>' to see details%s\n", colorGray, colorReset)
-	return nil
+func CommandWalk(ctx context.Context, streams Streams, config *models.ReplConfig, args []string) error {
+	if config.CurrentArea == "" {
+		return fmt.Errorf("explore an area first, then walk around it")
+	}
+	if config.Wild != nil {
+		return fmt.Errorf("a wild %s blocks the way! 'catch' it or 'flee'", config.Wild.Pokemon)
+	}
+
+	methods := config.Encounters.Methods(config.Version)
+	if len(methods) == 0 {
+		return render(streams, config, WalkResult{Area: config.CurrentArea})
+	}
+	method := "walk"
+	if len(args) > 0 {
+		method = args[0]
+	}
+	if !slices.Contains(methods, method) {
+		return fmt.Errorf("can't %s in %s, try one of: %s", method, config.CurrentArea, strings.Join(methods, ", "))
+	}
+
+	result := WalkResult{Area: config.CurrentArea, Method: method}
+	wild, found := config.Encounters.Step(method, config.Version, config.Rand.IntN)
+	if found {
+		config.Wild = &wild
+		result.Appeared = true
+		result.Pokemon = wild.Pokemon
+		result.Level = wild.Level
+	}
+	return render(streams, config, result)
 }
 
-func CommandExit(config *models.ReplConfig, args []string) error {
-	fmt.Printf("\n%s╔═══════════════════════════════════╗%s\n", colorCyan, colorReset)
-	fmt.Printf("%s║     Thanks for using Pokédex!    ║%s\n", colorCyan, colorReset)
-	fmt.Printf("%s║      You caught %3d Pokémon       ║%s\n", colorCyan, len(config.Pokedex), colorReset)
-	fmt.Printf("%s╚═══════════════════════════════════╝%s\n\n", colorCyan, colorReset)
-	os.Exit(0)
-	return nil
+func CommandFlee(ctx context.Context, streams Streams, config *models.ReplConfig, args []string) error {
+	if config.Wild == nil {
+		return fmt.Errorf("there's nothing to run from")
+	}
+	result := FleeResult{Pokemon: config.Wild.Pokemon}
+	config.Wild = nil
+	return render(streams, config, result)
 }
 
-func CommandHelp(config *models.ReplConfig, args []string) error {
-	fmt.Printf("%s╔═══════════════════════════════════════════════════════════╗%s\n", colorCyan, colorReset)
-	fmt.Printf("%s║                  POKÉDEX COMMANDS                         ║%s\n", colorCyan, colorReset)
-	fmt.Printf("%s╚═══════════════════════════════════════════════════════════╝%s\n\n", colorCyan, colorReset)
+func CommandMode(ctx context.Context, streams Streams, config *models.ReplConfig, args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "free":
+			config.Realistic = false
+		case "realistic":
+			config.Realistic = true
+		default:
+			return fmt.Errorf("usage: mode [free|realistic]")
+		}
+	}
 
-	// Group commands by category
-	navigation := []string{"map", "mapb"}
-	exploration := []string{"explore", "catch"}
-	collection := []string{"pokedex", "inspect"}
-	general := []string{"help", "exit"}
+	if config.Realistic {
+		return render(streams, config, ModeSetting{Mode: "realistic"})
+	}
+	return render(streams, config, ModeSetting{Mode: "free"})
+}
 
-	printCommandGroup("Navigation", navigation)
-	printCommandGroup("Exploration", exploration)
-	printCommandGroup("Collection", collection)
-	printCommandGroup("General", general)
+func CommandVersion(ctx context.Context, streams Streams, config *models.ReplConfig, args []string) error {
+	if len(args) == 0 {
+		return render(streams, config, versionSetting(config.Version, false))
+	}
 
+	version := ""
+	if args[0] != "any" {
+		versionResponse, err := config.PokeApiClient.GetVersion(ctx, args[0])
+		if err != nil {
+			return apiError(err, "game version", args[0])
+		}
+		version = versionResponse.Name
+	}
+	config.Version = version
+	if err := render(streams, config, versionSetting(version, true)); err != nil {
+		return err
+	}
+	return saveSession(config)
+}
+
+// versionSetting reports version, where empty means any version
+func versionSetting(version string, changed bool) VersionSetting {
+	if version == "" {
+		version = "any"
+	}
+	return VersionSetting{Version: version, changed: changed}
+}
+
+func CommandSeed(ctx context.Context, streams Streams, config *models.ReplConfig, args []string) error {
+	if len(args) == 0 {
+		return render(streams, config, SeedSetting{Seed: config.Seed})
+	}
+
+	seed := rand.Uint64()
+	if args[0] != "random" {
+		var err error
+		seed, err = strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("usage: seed [number|random]")
+		}
+	}
+	config.Reseed(seed)
+	return render(streams, config, SeedSetting{Seed: seed, changed: true})
+}
+
+func CommandCache(ctx context.Context, streams Streams, config *models.ReplConfig, args []string) error {
+	cache := config.PokeApiClient.Cache()
+
+	action := "stats"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "stats":
+		stats := cache.Stats()
+		return render(streams, config, CacheStats{Stats: stats, HitRate: hitRate(stats.Hits, stats.Misses)})
+	case "clear":
+		cache.Clear()
+		return render(streams, config, Notice{Message: "Cache cleared"})
+	case "list":
+		keys := cache.Keys()
+		if keys == nil {
+			keys = []string{}
+		}
+		return render(streams, config, CacheKeys{URLs: keys})
+	default:
+		return fmt.Errorf("usage: cache [stats|clear|list]")
+	}
+}
+
+// levelRange renders a level range as "3-5", or "4" if it's a single level
+func levelRange(minLevel, maxLevel int) string {
+	if minLevel == maxLevel {
+		return strconv.Itoa(minLevel)
+	}
+	return fmt.Sprintf("%d-%d", minLevel, maxLevel)
+}
+
+// hitRate returns the percentage of lookups served from the cache
+func hitRate(hits, misses uint64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses) * 100
+}
+
+// formatBytes renders a byte count in human readable units
+func formatBytes(n int) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := unit, 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+// saveSession persists the session if a store is configured
+func saveSession(config *models.ReplConfig) error {
+	if config.Store == nil {
+		return nil
+	}
+	if err := config.Store.Save(config); err != nil {
+		return fmt.Errorf("saving pokedex: %w", err)
+	}
 	return nil
 }
 
-func printCommandGroup(title string, commands []string) {
-	fmt.Printf("%s%s:%s\n", colorBold, title, colorReset)
+func CommandHelp(ctx context.Context, streams Streams, config *models.ReplConfig, args []string) error {
+	// Group commands by category
+	result := CommandList{Groups: []CommandGroup{
+		commandGroup("Navigation", []string{"regions", "region", "location", "map", "mapb"}),
+		commandGroup("Exploration", []string{"explore", "walk", "flee", "catch", "mode"}),
+		commandGroup("Collection", []string{"pokedex", "inspect"}),
+		commandGroup("General", []string{"help", "version", "seed", "cache", "exit"}),
+	}}
+	return render(streams, config, result)
+}
+
+func commandGroup(title string, commands []string) CommandGroup {
+	group := CommandGroup{Title: title, Commands: []CommandInfo{}}
 	for _, cmdName := range commands {
 		if cmd, exists := CommandsMap[cmdName]; exists {
-			fmt.Printf("  %s%-25s%s %s\n",
-				colorGreen, cmd.Name, colorReset, cmd.Description)
+			group.Commands = append(group.Commands, CommandInfo{Name: cmdName, Usage: cmd.Name, Description: cmd.Description})
 		}
 	}
-	fmt.Println()
+	return group
 }
@@ -0,0 +1,406 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"pokedexcli/internal/catchrate"
+	"pokedexcli/internal/clock"
+	"pokedexcli/internal/models"
+	"pokedexcli/internal/pokeapi"
+	"pokedexcli/internal/theme"
+	"strings"
+	"testing"
+	"time"
+)
+
+// discard throws away the output of commands whose effects are checked
+// on the config instead
+var discard = Streams{Out: io.Discard, Err: io.Discard}
+
+// newTestStreams returns streams that capture command output
+func newTestStreams() (Streams, *bytes.Buffer, *bytes.Buffer) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	return Streams{Out: out, Err: errOut}, out, errOut
+}
+
+// newTestConfig returns a session backed by a fake PokeAPI that knows a
+// single Pokémon, pikachu, which appears at every step in
+// viridian-forest-area
+func newTestConfig(t *testing.T, seed uint64) (*models.ReplConfig, *clock.Fake) {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pokemon/pikachu", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 25, "name": "pikachu", "base_experience": 112, "species": {"name": "pikachu"},
+			"sprites": {"front_default": "default.png", "versions": {"generation-i": {"yellow": {"front_default": "yellow.png"}}}}}`))
+	})
+	mux.HandleFunc("/pokemon-species/pikachu", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 25, "name": "pikachu", "capture_rate": 190}`))
+	})
+	mux.HandleFunc("/location/viridian-forest", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 321, "name": "viridian-forest", "region": {"name": "kanto"}, "areas": [{"name": "viridian-forest-area"}]}`))
+	})
+	mux.HandleFunc("/location-area", func(w http.ResponseWriter, r *http.Request) {
+		// Two pages, each naming its offset
+		offset := r.URL.Query().Get("offset")
+		next := "null"
+		if offset == "" || offset == "0" {
+			next = `"/location-area?offset=20&limit=20"`
+		}
+		fmt.Fprintf(w, `{"count": 40, "next": %s, "results": [{"name": "area-%s"}]}`, next, offset)
+	})
+	mux.HandleFunc("/version/yellow", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 3, "name": "yellow"}`))
+	})
+	mux.HandleFunc("/location-area/viridian-forest-area", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "viridian-forest-area", "encounter_method_rates": [{
+			"encounter_method": {"name": "walk"},
+			"version_details": [{"rate": 100, "version": {"name": "yellow"}}]
+		}], "pokemon_encounters": [{
+			"pokemon": {"name": "pikachu"},
+			"version_details": [{"version": {"name": "yellow"}, "max_chance": 100, "encounter_details": [
+				{"min_level": 3, "max_level": 5, "chance": 100, "method": {"name": "walk"}}
+			]}]
+		}]}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	fake := clock.NewFake(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	config := &models.ReplConfig{
+		Pokedex:       map[string]models.Pokemon{},
+		PokeApiClient: pokeapi.NewClient(pokeapi.WithBaseURL(server.URL), pokeapi.WithRateLimit(0, 0)),
+		CatchModel:    catchrate.NewModel(),
+		Clock:         fake,
+	}
+	config.Reseed(seed)
+	return config, fake
+}
+
+// throwsUntilCaught counts the throws needed to catch pikachu
+func throwsUntilCaught(t *testing.T, config *models.ReplConfig) int {
+	t.Helper()
+	for throws := 1; throws <= 100; throws++ {
+		if err := CommandCatch(context.Background(), discard, config, []string{"pikachu", "--hp", "100"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := config.Pokedex["pikachu"]; ok {
+			return throws
+		}
+	}
+	t.Fatalf("pikachu was never caught")
+	return 0
+}
+
+func TestCatchIsDeterministicForASeed(t *testing.T) {
+	for _, seed := range []uint64{1, 42, 1234} {
+		first, _ := newTestConfig(t, seed)
+		second, _ := newTestConfig(t, seed)
+		a, b := throwsUntilCaught(t, first), throwsUntilCaught(t, second)
+		if a != b {
+			t.Errorf("seed %d: took %d throws then %d", seed, a, b)
+		}
+		if first.Pokedex["pikachu"].CaughtAt != second.Pokedex["pikachu"].CaughtAt {
+			t.Errorf("seed %d: caught at %v then %v", seed, first.Pokedex["pikachu"].CaughtAt, second.Pokedex["pikachu"].CaughtAt)
+		}
+	}
+}
+
+func TestCatchUsesClock(t *testing.T) {
+	config, fake := newTestConfig(t, 1)
+	start := fake.Now()
+
+	if err := CommandCatch(context.Background(), discard, config, []string{"pikachu", "--ball", "master"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pokemon, ok := config.Pokedex["pikachu"]
+	if !ok {
+		t.Fatalf("a master ball should always catch")
+	}
+	// The throw and three wobbles each wait 800ms on the fake clock
+	if expected := start.Add(4 * 800 * time.Millisecond); !pokemon.CaughtAt.Equal(expected) {
+		t.Errorf("CaughtAt == %v, expected %v", pokemon.CaughtAt, expected)
+	}
+}
+
+func TestCommandSeed(t *testing.T) {
+	config, _ := newTestConfig(t, 1)
+	ctx := context.Background()
+
+	if err := CommandSeed(ctx, discard, config, []string{"99"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Seed != 99 {
+		t.Errorf("Seed == %d, expected 99", config.Seed)
+	}
+	if err := CommandSeed(ctx, discard, config, []string{"abc"}); err == nil {
+		t.Errorf("expected an error for a non-numeric seed")
+	}
+	if config.Seed != 99 {
+		t.Errorf("a bad seed should leave the seed alone, got %d", config.Seed)
+	}
+}
+
+func TestRealisticCatch(t *testing.T) {
+	config, _ := newTestConfig(t, 1)
+	config.Realistic = true
+	ctx := context.Background()
+	args := []string{"pikachu", "--ball", "master"}
+
+	if err := CommandCatch(ctx, discard, config, args); err == nil {
+		t.Errorf("expected an error before exploring")
+	}
+	if err := CommandExplore(ctx, discard, config, []string{"viridian-forest-area"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := CommandCatch(ctx, discard, config, []string{"mewtwo"}); err == nil {
+		t.Errorf("expected an error for a Pokémon not in the area")
+	}
+	if err := CommandCatch(ctx, discard, config, args); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pokemon, ok := config.Pokedex["pikachu"]
+	if !ok {
+		t.Fatalf("a master ball should always catch")
+	}
+	if pokemon.Level < 3 || pokemon.Level > 5 {
+		t.Errorf("Level == %d, expected 3-5", pokemon.Level)
+	}
+	if pokemon.CaughtIn != "viridian-forest-area" {
+		t.Errorf("CaughtIn == %q", pokemon.CaughtIn)
+	}
+}
+
+func TestCommandMode(t *testing.T) {
+	config, _ := newTestConfig(t, 1)
+	ctx := context.Background()
+
+	if err := CommandMode(ctx, discard, config, []string{"realistic"}); err != nil || !config.Realistic {
+		t.Errorf("expected realistic mode, got %v (err %v)", config.Realistic, err)
+	}
+	if err := CommandMode(ctx, discard, config, []string{"free"}); err != nil || config.Realistic {
+		t.Errorf("expected free mode, got %v (err %v)", config.Realistic, err)
+	}
+	if err := CommandMode(ctx, discard, config, []string{"hard"}); err == nil {
+		t.Errorf("expected an error for an unknown mode")
+	}
+}
+
+func TestWalkAndFlee(t *testing.T) {
+	config, _ := newTestConfig(t, 1)
+	ctx := context.Background()
+
+	if err := CommandWalk(ctx, discard, config, nil); err == nil {
+		t.Errorf("expected an error before exploring")
+	}
+	if err := CommandExplore(ctx, discard, config, []string{"viridian-forest-area"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := CommandWalk(ctx, discard, config, []string{"surf"}); err == nil {
+		t.Errorf("expected an error for a method the area doesn't have")
+	}
+
+	if err := CommandWalk(ctx, discard, config, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Wild == nil || config.Wild.Pokemon != "pikachu" {
+		t.Fatalf("expected a wild pikachu, got %+v", config.Wild)
+	}
+	if err := CommandWalk(ctx, discard, config, nil); err == nil {
+		t.Errorf("expected the wild Pokémon to block the way")
+	}
+	if err := CommandFlee(ctx, discard, config, nil); err != nil || config.Wild != nil {
+		t.Errorf("expected to get away, got %+v (err %v)", config.Wild, err)
+	}
+	if err := CommandFlee(ctx, discard, config, nil); err == nil {
+		t.Errorf("expected an error with nothing to flee from")
+	}
+
+	// Catching without a name throws at the wild Pokémon
+	if err := CommandWalk(ctx, discard, config, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	level := config.Wild.Level
+	if err := CommandCatch(ctx, discard, config, []string{"--ball", "master"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Wild != nil {
+		t.Errorf("a caught Pokémon should no longer be wild")
+	}
+	if pokemon := config.Pokedex["pikachu"]; pokemon.Level != level {
+		t.Errorf("Level == %d, expected %d", pokemon.Level, level)
+	}
+
+	// Meeting one that's already caught doesn't block the next walk
+	if err := CommandWalk(ctx, discard, config, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := CommandCatch(ctx, discard, config, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Wild != nil {
+		t.Errorf("an already caught Pokémon should no longer be wild")
+	}
+	if err := CommandWalk(ctx, discard, config, nil); err != nil {
+		t.Errorf("expected to walk on, got %v", err)
+	}
+}
+
+func TestCommandVersion(t *testing.T) {
+	config, _ := newTestConfig(t, 1)
+	ctx := context.Background()
+
+	if err := CommandVersion(ctx, discard, config, []string{"purple"}); err == nil {
+		t.Errorf("expected an error for an unknown version")
+	}
+	if err := CommandVersion(ctx, discard, config, []string{"yellow"}); err != nil || config.Version != "yellow" {
+		t.Fatalf("expected yellow, got %q (err %v)", config.Version, err)
+	}
+
+	// Pikachu lives in the forest in yellow, and looks like it did in yellow
+	if err := CommandExplore(ctx, discard, config, []string{"viridian-forest-area"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	config.Realistic = true
+	if err := CommandCatch(ctx, discard, config, []string{"pikachu", "--ball", "master"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sprite := config.Pokedex["pikachu"].Sprite; sprite != "yellow.png" {
+		t.Errorf("Sprite == %q, expected the yellow sprite", sprite)
+	}
+
+	if err := CommandVersion(ctx, discard, config, []string{"any"}); err != nil || config.Version != "" {
+		t.Errorf("expected any version, got %q (err %v)", config.Version, err)
+	}
+}
+
+func TestCommandLocation(t *testing.T) {
+	config, _ := newTestConfig(t, 1)
+	ctx := context.Background()
+
+	if err := CommandLocation(ctx, discard, config, nil); err == nil {
+		t.Errorf("expected a usage error without a location")
+	}
+	if err := CommandLocation(ctx, discard, config, []string{"viridian-forest"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := CommandLocation(ctx, discard, config, []string{"atlantis"}); err == nil {
+		t.Errorf("expected an error for an unknown location")
+	}
+}
+
+func TestCommandOutput(t *testing.T) {
+	cases := []struct {
+		name     string
+		commands [][]string
+		// out holds text expected in the output of the last command
+		out []string
+	}{
+		{
+			name:     "explore lists the area",
+			commands: [][]string{{"explore", "viridian-forest-area"}},
+			out:      []string{"Exploring viridian-forest-area...", "Pokémon Found in viridian-forest-area", "pikachu"},
+		},
+		{
+			name:     "explore shows encounter details for a version",
+			commands: [][]string{{"version", "yellow"}, {"explore", "viridian-forest-area"}},
+			out:      []string{"Lv. 3-5", "100%", "walk"},
+		},
+		{
+			name:     "catch animates the throw",
+			commands: [][]string{{"catch", "pikachu", "--ball", "master"}},
+			out:      []string{"Throwing a master ball at pikachu...", "Wobble... Wobble... Wobble...", "Gotcha! pikachu was caught!"},
+		},
+		{
+			name:     "pokedex lists catches",
+			commands: [][]string{{"catch", "pikachu", "pika", "--ball", "master"}, {"pokedex"}},
+			out:      []string{"YOUR POKÉDEX (  1)", "pika", "(pikachu)"},
+		},
+		{
+			name:     "inspect shows the catch",
+			commands: [][]string{{"catch", "pikachu", "--ball", "master"}, {"inspect", "pikachu"}},
+			out:      []string{"PIKACHU", "No.:", "25", "Caught:", "2024-05-01", "With a master ball at 100.0% odds"},
+		},
+		{
+			name:     "walk meets a wild pokemon",
+			commands: [][]string{{"explore", "viridian-forest-area"}, {"walk"}},
+			out:      []string{"A wild pikachu (Lv. ", "Use 'catch'"},
+		},
+		{
+			name:     "seed",
+			commands: [][]string{{"seed", "42"}, {"seed"}},
+			out:      []string{"Seed:", "42", "--seed 42"},
+		},
+		{
+			name:     "help",
+			commands: [][]string{{"help"}},
+			out:      []string{"Navigation:", "explore <area_name>", "cache [stats|clear|list]", "[--status <status>]" + colorReset + "\n", "Attempt to catch a Pokémon, or"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config, _ := newTestConfig(t, 1)
+			ctx := context.Background()
+			var out *bytes.Buffer
+			for _, words := range c.commands {
+				var streams Streams
+				streams, out, _ = newTestStreams()
+				if err := CommandsMap[words[0]].Callback(ctx, streams, config, words[1:]); err != nil {
+					t.Fatalf("%v: unexpected error: %v", words, err)
+				}
+			}
+			for _, expected := range c.out {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("expected %q in output:\n%s", expected, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestCommandExitReportsSaveErrors(t *testing.T) {
+	config, _ := newTestConfig(t, 1)
+	config.Store = failingStore{}
+	streams, out, errOut := newTestStreams()
+
+	if err := CommandExit(context.Background(), streams, config, nil); err != ErrExit {
+		t.Fatalf("expected ErrExit, got %v", err)
+	}
+	if !strings.Contains(out.String(), "Thanks for using Pokédex!") {
+		t.Errorf("expected a goodbye in the output, got:\n%s", out.String())
+	}
+	if !strings.Contains(errOut.String(), "saving pokedex: disk full") {
+		t.Errorf("expected the save error on the error stream, got %q", errOut.String())
+	}
+}
+
+// failingStore can't save anything
+type failingStore struct{}
+
+func (failingStore) Load(config *models.ReplConfig) error { return nil }
+func (failingStore) Save(config *models.ReplConfig) error { return errors.New("disk full") }
+
+func TestPlainOutput(t *testing.T) {
+	usePalette(theme.Palette{})
+	t.Cleanup(func() {
+		palette, _ := theme.Default().Palette(theme.Basic)
+		usePalette(palette)
+	})
+
+	config, _ := newTestConfig(t, 1)
+	streams, out, _ := newTestStreams()
+	if err := CommandCatch(context.Background(), streams, config, []string{"pikachu", "--ball", "master"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := CommandInspect(context.Background(), streams, config, []string{"pikachu"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(out.String(), "\033[") {
+		t.Errorf("expected no escape sequences without colors, got:\n%q", out.String())
+	}
+}
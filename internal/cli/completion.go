@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"pokedexcli/internal/catchrate"
+	"pokedexcli/internal/lineedit"
+	"pokedexcli/internal/models"
+	"sort"
+	"strings"
+)
+
+// subcommands are the fixed choices for a command's first argument
+var subcommands = map[string][]string{
+	"map":   {"first", "last"},
+	"mode":  {"free", "realistic"},
+	"seed":  {"random"},
+	"cache": {"stats", "clear", "list"},
+}
+
+// newCompleter completes command names, then the arguments of commands
+// that take a name
+func newCompleter(config *models.ReplConfig) lineedit.Completer {
+	return func(line string) (int, []string) {
+		return completeLine(config, line)
+	}
+}
+
+// completeLine returns where the word before the cursor starts and the
+// words it could be. line is the text before the cursor.
+func completeLine(config *models.ReplConfig, line string) (int, []string) {
+	start := strings.LastIndex(line, " ") + 1
+	word := strings.ToLower(line[start:])
+	words := CleanInput(line[:start])
+
+	choices := []string{}
+	if len(words) == 0 {
+		for name := range CommandsMap {
+			choices = append(choices, name)
+		}
+	} else {
+		choices = argumentChoices(config, words[0], words[1:])
+	}
+
+	seen := map[string]bool{}
+	candidates := []string{}
+	for _, choice := range choices {
+		if choice != "" && strings.HasPrefix(choice, word) && !seen[choice] {
+			seen[choice] = true
+			candidates = append(candidates, choice)
+		}
+	}
+	sort.Strings(candidates)
+	return start, candidates
+}
+
+// argumentChoices returns what can follow args in a command. Only the
+// first argument and the catch --ball flag are completed.
+func argumentChoices(config *models.ReplConfig, command string, args []string) []string {
+	if command == "catch" && len(args) > 0 && args[len(args)-1] == "--ball" {
+		balls := []string{}
+		for _, ball := range catchrate.Balls() {
+			balls = append(balls, strings.TrimSuffix(string(ball), "-ball"))
+		}
+		return balls
+	}
+	if len(args) > 0 {
+		return nil
+	}
+
+	client := config.PokeApiClient
+	switch command {
+	case "explore":
+		return append(client.KnownNames("location-area"), config.CurrentArea)
+	case "catch":
+		// Pokémon that were looked up or live here, but not caught yet
+		choices := []string{}
+		names := append(client.KnownNames("pokemon"), config.Encounters.Pokemon(config.Version)...)
+		if config.Wild != nil {
+			names = append(names, config.Wild.Pokemon)
+		}
+		for _, name := range names {
+			if _, caught := config.Pokedex[name]; !caught {
+				choices = append(choices, name)
+			}
+		}
+		return choices
+	case "inspect":
+		choices := []string{}
+		for name := range config.Pokedex {
+			choices = append(choices, name)
+		}
+		return choices
+	case "region":
+		return client.KnownNames("region")
+	case "location":
+		return client.KnownNames("location")
+	case "version":
+		return append(client.KnownNames("version"), "any")
+	case "walk":
+		return config.Encounters.Methods(config.Version)
+	default:
+		return subcommands[command]
+	}
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCompleteLine(t *testing.T) {
+	config, _ := newTestConfig(t, 1)
+	ctx := context.Background()
+	// Fill the cache and the Pokédex the way a session would
+	if err := CommandExplore(ctx, discard, config, []string{"viridian-forest-area"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := CommandLocation(ctx, discard, config, []string{"viridian-forest"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		line       string
+		start      int
+		candidates []string
+	}{
+		{line: "", start: 0, candidates: nil},
+		{line: "ex", start: 0, candidates: []string{"exit", "explore"}},
+		{line: "MA", start: 0, candidates: []string{"map", "mapb"}},
+		{line: "explore ", start: 8, candidates: []string{"viridian-forest-area"}},
+		{line: "explore vir", start: 8, candidates: []string{"viridian-forest-area"}},
+		{line: "location v", start: 9, candidates: []string{"viridian-forest"}},
+		{line: "catch p", start: 6, candidates: []string{"pikachu"}},
+		{line: "catch pikachu --ball ul", start: 21, candidates: []string{"ultra"}},
+		{line: "catch pikachu pika", start: 14, candidates: []string{}},
+		{line: "inspect ", start: 8, candidates: []string{}},
+		{line: "mode r", start: 5, candidates: []string{"realistic"}},
+		{line: "walk ", start: 5, candidates: []string{"walk"}},
+		{line: "throw ", start: 6, candidates: []string{}},
+	}
+	for _, c := range cases {
+		start, candidates := completeLine(config, c.line)
+		if c.candidates == nil {
+			// Every command
+			if len(candidates) != len(CommandsMap) {
+				t.Errorf("completeLine(%q) == %v, expected every command", c.line, candidates)
+			}
+			continue
+		}
+		if start != c.start || strings.Join(candidates, ",") != strings.Join(c.candidates, ",") {
+			t.Errorf("completeLine(%q) == %d, %v, expected %d, %v", c.line, start, candidates, c.start, c.candidates)
+		}
+	}
+
+	// Caught Pokémon move from catch to inspect
+	if err := CommandCatch(ctx, discard, config, []string{"pikachu", "--ball", "master"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, candidates := completeLine(config, "catch "); len(candidates) != 0 {
+		t.Errorf("expected caught Pokémon not to be offered to catch, got %v", candidates)
+	}
+	if _, candidates := completeLine(config, "inspect "); strings.Join(candidates, ",") != "pikachu" {
+		t.Errorf("expected caught Pokémon to be offered to inspect, got %v", candidates)
+	}
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pokedexcli/internal/pokeapi"
+)
+
+// ErrExit is returned by a command to end the session
+var ErrExit = errors.New("exit")
+
+// apiError turns an error from the pokeapi client into a message the
+// player can act on. kind and name describe what was being looked up,
+// e.g. "Pokémon" and "pikachuu"; name may be empty.
+func apiError(err error, kind, name string) error {
+	if err == nil {
+		return nil
+	}
+	// Cancellation is reported by the REPL itself
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	var statusErr *pokeapi.StatusError
+	switch {
+	case errors.Is(err, pokeapi.ErrNotFound):
+		if name == "" {
+			return fmt.Errorf("no %s found", kind)
+		}
+		return fmt.Errorf("no %s named '%s', check the spelling", kind, name)
+	case errors.Is(err, pokeapi.ErrRateLimited):
+		return fmt.Errorf("PokeAPI is rate limiting us, wait a moment and try again")
+	case errors.As(err, &statusErr):
+		return fmt.Errorf("PokeAPI had a problem (status %d), try again later", statusErr.StatusCode)
+	case errors.Is(err, pokeapi.ErrDecode):
+		return fmt.Errorf("PokeAPI sent a response we couldn't read: %w", err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("PokeAPI took too long to respond, try again")
+	default:
+		return fmt.Errorf("couldn't reach PokeAPI: %w", err)
+	}
+}
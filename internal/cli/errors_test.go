@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pokedexcli/internal/pokeapi"
+	"strings"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected string
+	}{
+		{
+			err:      &pokeapi.StatusError{URL: "https://pokeapi.co/api/v2/pokemon/pikachuu", StatusCode: 404},
+			expected: "no Pokémon named 'pikachuu'",
+		},
+		{
+			err:      &pokeapi.StatusError{StatusCode: 429},
+			expected: "rate limiting",
+		},
+		{
+			err:      &pokeapi.StatusError{StatusCode: 503},
+			expected: "status 503",
+		},
+		{
+			err:      fmt.Errorf("%w: bad body", pokeapi.ErrDecode),
+			expected: "couldn't read",
+		},
+		{
+			err:      errors.New("dial tcp: no route to host"),
+			expected: "couldn't reach PokeAPI",
+		},
+	}
+
+	for _, c := range cases {
+		actual := apiError(c.err, "Pokémon", "pikachuu")
+		if actual == nil || !strings.Contains(actual.Error(), c.expected) {
+			t.Errorf("apiError(%v) == %v, expected it to mention %q", c.err, actual, c.expected)
+		}
+	}
+
+	if err := apiError(context.Canceled, "Pokémon", "pikachu"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancellation to pass through, got %v", err)
+	}
+}
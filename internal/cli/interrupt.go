@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"sync"
+)
+
+// interruptHandler routes Ctrl-C to the running command so it can be
+// cancelled without killing the REPL
+type interruptHandler struct {
+	mutex  sync.Mutex
+	cancel context.CancelFunc
+}
+
+// watch handles signals until the channel is closed. A signal cancels the
+// running command; with no command running, idle is called instead.
+func (h *interruptHandler) watch(signals <-chan os.Signal, idle func()) {
+	for range signals {
+		h.mutex.Lock()
+		cancel := h.cancel
+		h.mutex.Unlock()
+
+		if cancel != nil {
+			cancel()
+		} else {
+			idle()
+		}
+	}
+}
+
+// commandContext returns a context that is cancelled by the next
+// interrupt. The returned func must be called once the command finishes.
+func (h *interruptHandler) commandContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	h.mutex.Lock()
+	h.cancel = cancel
+	h.mutex.Unlock()
+
+	return ctx, func() {
+		h.mutex.Lock()
+		h.cancel = nil
+		h.mutex.Unlock()
+		cancel()
+	}
+}
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestInterruptCancelsRunningCommand(t *testing.T) {
+	handler := &interruptHandler{}
+	signals := make(chan os.Signal)
+	idle := make(chan struct{}, 1)
+	go handler.watch(signals, func() { idle <- struct{}{} })
+	defer close(signals)
+
+	ctx, done := handler.commandContext(context.Background())
+	signals <- os.Interrupt
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("expected interrupt to cancel the command context")
+	}
+	done()
+
+	// With no command running the interrupt goes to idle
+	signals <- os.Interrupt
+	select {
+	case <-idle:
+	case <-time.After(time.Second):
+		t.Fatalf("expected interrupt at the prompt to call idle")
+	}
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"pokedexcli/internal/models"
+	"pokedexcli/internal/pokeapi"
+	"strconv"
+	"testing"
+)
+
+// newMapConfig returns a session whose PokeAPI has count location areas.
+// The offset and limit of the last list request are reported through
+// requested.
+func newMapConfig(t *testing.T, count int) (*models.ReplConfig, *[2]int) {
+	t.Helper()
+	requested := &[2]int{}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		requested[0], requested[1] = offset, limit
+
+		names := ""
+		for i := offset; i < min(offset+limit, count); i++ {
+			if names != "" {
+				names += ","
+			}
+			names += fmt.Sprintf(`{"name": "area-%d"}`, i)
+		}
+		next, previous := "null", "null"
+		if offset+limit < count {
+			next = fmt.Sprintf(`"%s/location-area?offset=%d&limit=%d"`, server.URL, offset+limit, limit)
+		}
+		if offset > 0 {
+			previous = fmt.Sprintf(`"%s/location-area?offset=%d&limit=%d"`, server.URL, max(0, offset-limit), limit)
+		}
+		fmt.Fprintf(w, `{"count": %d, "next": %s, "previous": %s, "results": [%s]}`, count, next, previous, names)
+	}))
+	t.Cleanup(server.Close)
+
+	config := &models.ReplConfig{
+		Pokedex:       map[string]models.Pokemon{},
+		PokeApiClient: pokeapi.NewClient(pokeapi.WithBaseURL(server.URL), pokeapi.WithRateLimit(0, 0)),
+	}
+	return config, requested
+}
+
+func TestCommandMap(t *testing.T) {
+	cases := []struct {
+		name     string
+		args     [][]string
+		offset   int
+		limit    int
+		lastPage bool
+	}{
+		{name: "first page", args: [][]string{{}}, offset: 0, limit: 20},
+		{name: "cursor", args: [][]string{{}, {}}, offset: 20, limit: 20},
+		{name: "jump to page", args: [][]string{{"--page", "3"}}, offset: 40, limit: 20, lastPage: true},
+		{name: "page size", args: [][]string{{"--limit", "10"}, {}}, offset: 10, limit: 10},
+		{name: "page size keeps cursor", args: [][]string{{}, {"--limit=5"}}, offset: 20, limit: 5},
+		{name: "last", args: [][]string{{"last"}}, offset: 40, limit: 20, lastPage: true},
+		{name: "last with limit", args: [][]string{{"last", "--limit", "15"}}, offset: 30, limit: 15, lastPage: true},
+		{name: "first", args: [][]string{{"--page", "2"}, {"first"}}, offset: 0, limit: 20},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config, requested := newMapConfig(t, 45)
+			for _, args := range c.args {
+				if err := CommandMap(context.Background(), discard, config, args); err != nil {
+					t.Fatalf("map %v: unexpected error: %v", args, err)
+				}
+			}
+			if requested[0] != c.offset || requested[1] != c.limit {
+				t.Errorf("requested offset %d limit %d, expected %d and %d", requested[0], requested[1], c.offset, c.limit)
+			}
+			if (config.Next == "") != c.lastPage {
+				t.Errorf("Next == %q, expected last page %v", config.Next, c.lastPage)
+			}
+		})
+	}
+}
+
+func TestCommandMapErrors(t *testing.T) {
+	cases := [][]string{
+		{"--page", "0"},
+		{"--page", "x"},
+		{"--page", "4"},
+		{"--limit", "0"},
+		{"--limit", "101"},
+		{"middle"},
+		{"--offset", "3"},
+	}
+	for _, args := range cases {
+		config, _ := newMapConfig(t, 45)
+		if err := CommandMap(context.Background(), discard, config, args); err == nil {
+			t.Errorf("map %v: expected an error", args)
+		}
+	}
+}
+
+func TestCommandMapb(t *testing.T) {
+	config, requested := newMapConfig(t, 45)
+	ctx := context.Background()
+
+	if err := CommandMap(ctx, discard, config, []string{"--page", "3"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := CommandMapb(ctx, discard, config, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requested[0] != 20 {
+		t.Errorf("mapb requested offset %d, expected 20", requested[0])
+	}
+}
+
+func TestParseMapArgs(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected mapRequest
+		err      bool
+	}{
+		{args: nil, expected: mapRequest{}},
+		{args: []string{"first"}, expected: mapRequest{page: 1}},
+		{args: []string{"last", "--limit", "50"}, expected: mapRequest{last: true, limit: 50}},
+		{args: []string{"--page=7", "--limit=10"}, expected: mapRequest{page: 7, limit: 10}},
+		{args: []string{"first", "--page", "2"}, err: true},
+		{args: []string{"first", "last"}, err: true},
+		{args: []string{"canalave-city-area"}, err: true},
+		{args: []string{"--page"}, err: true},
+	}
+	for _, c := range cases {
+		request, err := parseMapArgs(c.args)
+		if (err != nil) != c.err {
+			t.Errorf("parseMapArgs(%v) error == %v, expected error %v", c.args, err, c.err)
+			continue
+		}
+		if request != c.expected {
+			t.Errorf("parseMapArgs(%v) == %+v, expected %+v", c.args, request, c.expected)
+		}
+	}
+}
@@ -2,25 +2,93 @@ package cli
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand/v2"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"pokedexcli/internal/catchrate"
+	"pokedexcli/internal/clock"
+	"pokedexcli/internal/lineedit"
 	"pokedexcli/internal/models"
+	"pokedexcli/internal/output"
 	"pokedexcli/internal/pokeapi"
+	"pokedexcli/internal/pokecache"
+	"pokedexcli/internal/storage"
+	"pokedexcli/internal/theme"
 	"strings"
+	"time"
 )
 
-const (
-	colorReset  = "\033[0m"
-	colorRed    = "\033[31m"
-	colorGreen  = "\033[32m"
-	colorYellow = "\033[33m"
-	colorBlue   = "\033[34m"
-	colorPurple = "\033[35m"
-	colorCyan   = "\033[36m"
-	colorGray   = "\033[90m"
-	colorBold   = "\033[1m"
+// diskCacheTTL is how long API responses are kept on disk. PokeAPI data
+// rarely changes, so a day keeps restarts fast without going stale.
+const diskCacheTTL = 24 * time.Hour
+
+// The escape sequences of the current theme, set by usePalette. They are
+// empty when output isn't colored.
+var (
+	colorReset  string
+	colorRed    string
+	colorGreen  string
+	colorYellow string
+	colorCyan   string
+	colorGray   string
+	colorBold   string
+	// typeColors maps Pokémon types to their colors
+	typeColors map[string]string
 )
 
+func init() {
+	palette, _ := theme.Default().Palette(theme.Basic)
+	usePalette(palette)
+}
+
+// usePalette switches output to the colors of palette
+func usePalette(palette theme.Palette) {
+	colorReset = palette.Reset
+	colorRed = palette.Colors["red"]
+	colorGreen = palette.Colors["green"]
+	colorYellow = palette.Colors["yellow"]
+	colorCyan = palette.Colors["cyan"]
+	colorGray = palette.Colors["gray"]
+	colorBold = palette.Colors["bold"]
+	typeColors = palette.Types
+}
+
+// setupColors picks the palette for opts: none when output isn't colored,
+// otherwise the user's theme rendered for the terminal
+func setupColors(opts Options) error {
+	mode := opts.Color
+	if mode == "" {
+		mode = theme.Auto
+	}
+	if !theme.Enabled(mode, isTerminal(os.Stdout), os.Getenv) {
+		usePalette(theme.Palette{})
+		return nil
+	}
+
+	path := opts.Theme
+	if path == "" {
+		var err error
+		if path, err = theme.DefaultPath(); err != nil {
+			return err
+		}
+	}
+	userTheme, err := theme.Load(path)
+	if err != nil {
+		return err
+	}
+	palette, err := userTheme.Palette(theme.DetectDepth(os.Getenv))
+	if err != nil {
+		return err
+	}
+	usePalette(palette)
+	return nil
+}
+
 // CleanInput normalizes user input by lowercasing and splitting into words
 func CleanInput(input string) []string {
 	lowered := strings.ToLower(input)
@@ -29,7 +97,7 @@ func CleanInput(input string) []string {
 }
 
 // printBanner displays the Pokedex ASCII banner
-func printBanner() {
+func printBanner(w io.Writer) {
 	banner := `
 ╔═══════════════════════════════════════╗
 ║                                       ║
@@ -44,31 +112,31 @@ func printBanner() {
 ║                                       ║
 ╚═══════════════════════════════════════╝
 `
-	fmt.Print(colorCyan + banner + colorReset)
-	fmt.Println("\nType 'help' to see available commands\n")
+	fmt.Fprint(w, colorCyan+banner+colorReset)
+	fmt.Fprint(w, "\nType 'help' to see available commands\n\n")
 }
 
-// printPrompt displays a styled prompt with status info
-func printPrompt(config *models.ReplConfig) {
+// prompt returns a styled prompt with status info
+func prompt(config *models.ReplConfig) string {
 	caughtCount := len(config.Pokedex)
-	fmt.Printf("%s[%d caught]%s %sPokedex >%s ",
+	return fmt.Sprintf("%s[%d caught]%s %sPokedex >%s ",
 		colorGray, caughtCount, colorReset,
 		colorGreen, colorReset)
 }
 
 // printError displays an error message in red
-func printError(err error) {
-	fmt.Printf("%s✗ Error:%s %v\n", colorRed, colorReset, err)
+func printError(w io.Writer, err error) {
+	fmt.Fprintf(w, "%s✗ Error:%s %v\n", colorRed, colorReset, err)
 }
 
 // printSuccess displays a success message in green
-func printSuccess(message string) {
-	fmt.Printf("%s✓%s %s\n", colorGreen, colorReset, message)
+func printSuccess(w io.Writer, message string) {
+	fmt.Fprintf(w, "%s✓%s %s\n", colorGreen, colorReset, message)
 }
 
 // printWarning displays a warning message in yellow
-func printWarning(message string) {
-	fmt.Printf("%s⚠%s %s\n", colorYellow, colorReset, message)
+func printWarning(w io.Writer, message string) {
+	fmt.Fprintf(w, "%s⚠%s %s\n", colorYellow, colorReset, message)
 }
 
 // findSimilarCommand suggests similar commands for typos
@@ -89,54 +157,317 @@ func min(a, b int) int {
 	return b
 }
 
-// clearScreen clears the terminal (works on Unix-like systems)
-func clearScreen() {
-	fmt.Print("\033[H\033[2J")
+// clearScreen clears the terminal (works on Unix-like systems). Output
+// redirected to a file or a pipe is left alone.
+func clearScreen(w io.Writer) {
+	if f, ok := w.(*os.File); !ok || !isTerminal(f) {
+		return
+	}
+	fmt.Fprint(w, "\033[H\033[2J")
+}
+
+// clientOptions configures the PokeAPI client from the environment.
+// POKEAPI_BASE_URL points the CLI at a PokeAPI mirror.
+func clientOptions() []pokeapi.Option {
+	opts := newResponseCache()
+	if baseURL := os.Getenv("POKEAPI_BASE_URL"); baseURL != "" {
+		opts = append(opts, pokeapi.WithBaseURL(baseURL))
+	}
+	return opts
+}
+
+// newResponseCache returns client options for an on-disk response cache,
+// or none to fall back to the in-memory default
+func newResponseCache() []pokeapi.Option {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		printWarning(os.Stderr, fmt.Sprintf("API responses will not be cached on disk: %v", err))
+		return nil
+	}
+	cache, err := pokecache.NewDiskCache(filepath.Join(cacheDir, "pokedexcli", "responses"), diskCacheTTL)
+	if err != nil {
+		printWarning(os.Stderr, fmt.Sprintf("API responses will not be cached on disk: %v", err))
+		return nil
+	}
+	return []pokeapi.Option{pokeapi.WithCache(cache)}
+}
+
+// Exit codes returned by Run
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitUsage       = 2
+	exitInterrupted = 130
+)
+
+// Options configures a REPL session
+type Options struct {
+	// Seed seeds the random game mechanics; nil picks a random seed
+	Seed *uint64
+	// Realistic starts the session in realistic mode
+	Realistic bool
+	// Args runs a single command, e.g. ["inspect", "pikachu"], instead of
+	// the interactive REPL
+	Args []string
+	// Script runs the commands in a file, one per line. "-" reads them
+	// from stdin, which is also used when stdin isn't a terminal.
+	Script string
+	// Output renders command results, the colored table if nil
+	Output output.Formatter
+	// Color is when to color output, auto if empty
+	Color theme.Mode
+	// Theme is the theme file to use instead of the default location
+	Theme string
 }
 
-// StartREPL initializes and starts the REPL loop
-func StartREPL() {
-	reader := bufio.NewReader(os.Stdin)
+// Run starts a session and returns the process exit code. With Args it
+// runs one command, with a script or piped stdin it runs a batch of
+// commands, otherwise it starts the interactive REPL.
+func Run(opts Options) int {
+	interactive := len(opts.Args) == 0 && opts.Script == "" && isTerminal(os.Stdin)
+	// A broken theme shouldn't stop anyone from playing
+	if err := setupColors(opts); err != nil {
+		printWarning(os.Stderr, fmt.Sprintf("Using the default colors: %v", err))
+	}
+	config := newSession(opts, interactive)
+	streams := StdStreams()
+
+	// Ctrl-C cancels the running command; at the prompt it exits as before
+	interrupts := &interruptHandler{}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	go interrupts.watch(signals, func() {
+		if !interactive {
+			os.Exit(exitInterrupted)
+		}
+		fmt.Fprintln(streams.Out)
+		CommandExit(context.Background(), streams, config, nil)
+		os.Exit(exitOK)
+	})
+
+	switch {
+	case len(opts.Args) > 0:
+		return runCommand(interrupts, streams, config, CleanInput(strings.Join(opts.Args, " ")))
+	case opts.Script != "" && opts.Script != "-":
+		script, err := os.Open(opts.Script)
+		if err != nil {
+			printError(streams.Err, fmt.Errorf("opening script: %w", err))
+			return exitUsage
+		}
+		defer script.Close()
+		return runScript(interrupts, streams, config, script)
+	case !interactive:
+		return runScript(interrupts, streams, config, os.Stdin)
+	default:
+		return startREPL(interrupts, streams, config, newEditor(streams, config))
+	}
+}
+
+// newSession builds the session state and restores the previous session
+func newSession(opts Options, interactive bool) *models.ReplConfig {
 	config := &models.ReplConfig{
 		Pokedex:       map[string]models.Pokemon{},
-		PokeApiClient: pokeapi.NewClient(),
+		PokeApiClient: pokeapi.NewClient(clientOptions()...),
+		CatchModel:    catchrate.NewModel(),
+		Clock:         clock.Real{},
+		Realistic:     opts.Realistic,
+		Output:        opts.Output,
+	}
+	// Nobody is watching the catch animation in a script
+	if !interactive {
+		config.Clock = clock.NoSleep{}
+	}
+	if opts.Seed != nil {
+		config.Reseed(*opts.Seed)
+	} else {
+		config.Reseed(rand.Uint64())
 	}
 
-	clearScreen()
-	printBanner()
+	// Restore the previous session; without a save location the REPL
+	// still works, it just won't remember anything
+	savePath, err := storage.DefaultPath()
+	if err != nil {
+		printWarning(os.Stderr, fmt.Sprintf("Pokédex will not be saved: %v", err))
+	} else {
+		config.Store = storage.NewStore(savePath)
+		if err := config.Store.Load(config); err != nil {
+			// Don't overwrite a save file we couldn't read
+			printWarning(os.Stderr, fmt.Sprintf("Could not load saved Pokédex, saving is disabled: %v", err))
+			config.Store = nil
+		}
+	}
+	return config
+}
 
-	for {
-		printPrompt(config)
-		input, err := reader.ReadString('\n')
+// isTerminal reports whether f is an interactive terminal rather than a
+// pipe or a file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// unknownCommandError is returned by execute for a command that doesn't
+// exist
+type unknownCommandError struct {
+	command string
+}
+
+func (e *unknownCommandError) Error() string {
+	return fmt.Sprintf("unknown command '%s'. %s", e.command, commandHint(e.command))
+}
+
+// commandHint suggests what to type instead of an unknown command
+func commandHint(command string) string {
+	if similar := findSimilarCommand(command); similar != "" {
+		return fmt.Sprintf("Did you mean '%s'?", similar)
+	}
+	return "Type 'help' for available commands."
+}
+
+// execute runs the command named by words[0] with the rest as arguments
+func execute(interrupts *interruptHandler, streams Streams, config *models.ReplConfig, words []string) error {
+	command := words[0]
+	cmd, exists := CommandsMap[command]
+	if !exists {
+		return &unknownCommandError{command: command}
+	}
+
+	ctx, done := interrupts.commandContext(context.Background())
+	defer done()
+	return cmd.Callback(ctx, streams, config, words[1:])
+}
+
+// exitCode maps the error a command returned to an exit code
+func exitCode(err error) int {
+	var unknown *unknownCommandError
+	switch {
+	case err == nil, errors.Is(err, ErrExit):
+		return exitOK
+	case errors.Is(err, context.Canceled):
+		return exitInterrupted
+	case errors.As(err, &unknown):
+		return exitUsage
+	default:
+		return exitError
+	}
+}
+
+// runCommand runs a single command and returns the exit code
+func runCommand(interrupts *interruptHandler, streams Streams, config *models.ReplConfig, words []string) int {
+	if len(words) == 0 {
+		return exitOK
+	}
+	err := execute(interrupts, streams, config, words)
+	if errors.Is(err, ErrExit) {
+		return exitOK
+	}
+	if err != nil {
+		printError(streams.Err, err)
+	}
+	return endSession(streams, config, exitCode(err))
+}
+
+// runScript runs the commands read from script, one per line, stopping at
+// the first one that fails. Blank lines and lines starting with # are
+// skipped.
+func runScript(interrupts *interruptHandler, streams Streams, config *models.ReplConfig, script io.Reader) int {
+	scanner := bufio.NewScanner(script)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		err := execute(interrupts, streams, config, CleanInput(text))
+		if errors.Is(err, ErrExit) {
+			return exitOK
+		}
 		if err != nil {
-			printError(fmt.Errorf("reading input: %w", err))
+			printError(streams.Err, fmt.Errorf("line %d: %w", line, err))
+			return endSession(streams, config, exitCode(err))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		printError(streams.Err, fmt.Errorf("reading script: %w", err))
+		return endSession(streams, config, exitError)
+	}
+	return endSession(streams, config, exitOK)
+}
+
+// endSession saves the session after a command or script, since only some
+// commands save as they go, e.g. map doesn't save its cursor. It returns
+// code, or exitError if the save failed where everything else worked.
+func endSession(streams Streams, config *models.ReplConfig, code int) int {
+	if err := saveSession(config); err != nil {
+		printError(streams.Err, err)
+		if code == exitOK {
+			return exitError
+		}
+	}
+	return code
+}
+
+// newEditor returns a line editor for the terminal, with the history of
+// previous sessions and completion from the session state
+func newEditor(streams Streams, config *models.ReplConfig) *lineedit.Editor {
+	editor := lineedit.New(os.Stdin, streams.Out)
+	editor.Complete = newCompleter(config)
+
+	// Without a history file, history only lasts for this session
+	path, err := lineedit.DefaultHistoryPath()
+	if err == nil {
+		var history *lineedit.History
+		if history, err = lineedit.LoadHistory(path, lineedit.DefaultHistorySize); err == nil {
+			editor.History = history
+		}
+	}
+	if err != nil {
+		printWarning(streams.Err, fmt.Sprintf("History will not be saved: %v", err))
+	}
+	return editor
+}
+
+// startREPL reads commands from the editor until exit
+func startREPL(interrupts *interruptHandler, streams Streams, config *models.ReplConfig, editor *lineedit.Editor) int {
+	clearScreen(streams.Out)
+	printBanner(streams.Out)
+
+	for {
+		line, err := editor.ReadLine(prompt(config))
+		if errors.Is(err, io.EOF) || errors.Is(err, lineedit.ErrInterrupted) {
+			// Ctrl-D, or Ctrl-C at the prompt
+			fmt.Fprintln(streams.Out)
+			CommandExit(context.Background(), streams, config, nil)
+			return exitOK
+		}
+		if err != nil && line == "" {
+			printError(streams.Err, fmt.Errorf("reading input: %w", err))
 			continue
 		}
+		if err != nil {
+			printWarning(streams.Err, err.Error())
+		}
 
-		words := CleanInput(input)
+		words := CleanInput(line)
 		if len(words) == 0 {
 			continue
 		}
-
-		command := words[0]
-		args := words[1:]
-
-		cmd, exists := CommandsMap[command]
-		if !exists {
-			similar := findSimilarCommand(command)
-			if similar != "" {
-				printWarning(fmt.Sprintf("Unknown command '%s'. Did you mean '%s'?", command, similar))
-			} else {
-				printWarning(fmt.Sprintf("Unknown command '%s'. Type 'help' for available commands.", command))
-			}
+		if _, exists := CommandsMap[words[0]]; !exists {
+			printWarning(streams.Err, fmt.Sprintf("Unknown command '%s'. %s", words[0], commandHint(words[0])))
 			continue
 		}
 
-		fmt.Println() // Add spacing before command output
-		err = cmd.Callback(config, args)
-		if err != nil {
-			printError(err)
+		fmt.Fprintln(streams.Out) // Add spacing before command output
+		err = execute(interrupts, streams, config, words)
+		if errors.Is(err, ErrExit) {
+			return exitOK
+		}
+		if errors.Is(err, context.Canceled) {
+			fmt.Fprintln(streams.Out)
+			printWarning(streams.Err, "Cancelled")
+		} else if err != nil {
+			printError(streams.Err, err)
 		}
-		fmt.Println() // Add spacing after command output
+		fmt.Fprintln(streams.Out) // Add spacing after command output
 	}
 }
@@ -0,0 +1,492 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"pokedexcli/internal/models"
+	"pokedexcli/internal/output"
+	"pokedexcli/internal/pokecache"
+	"sort"
+	"strings"
+)
+
+// render prints a command's result in the session's output format
+func render(streams Streams, config *models.ReplConfig, result any) error {
+	formatter := config.Output
+	if formatter == nil {
+		formatter = output.Table{}
+	}
+	return formatter.Format(streams.Out, result)
+}
+
+// progress prints a status update, like an animation, that only means
+// something to a person watching the table output
+func progress(streams Streams, config *models.ReplConfig, format string, args ...any) {
+	if output.IsTable(config.Output) {
+		fmt.Fprintf(streams.Out, format, args...)
+	}
+}
+
+// Notice is a result that is only a message
+type Notice struct {
+	Message string `json:"message"`
+	warning bool
+}
+
+func (n Notice) WriteTable(w io.Writer) error {
+	if n.warning {
+		fmt.Fprintf(w, "%s⚠ %s%s\n", colorYellow, n.Message, colorReset)
+	} else {
+		fmt.Fprintf(w, "%s✓ %s%s\n", colorGreen, n.Message, colorReset)
+	}
+	return nil
+}
+
+// LocationPage is a page of location areas from map or mapb
+type LocationPage struct {
+	Page      int      `json:"page"`
+	Pages     int      `json:"pages"`
+	Count     int      `json:"count"`
+	Locations []string `json:"locations"`
+	// hint tells the player how to keep paging
+	hint string
+}
+
+func (p LocationPage) WriteTable(w io.Writer) error {
+	fmt.Fprintf(w, "%s═══ Locations ═══%s", colorCyan, colorReset)
+	if p.Count > 0 {
+		fmt.Fprintf(w, " %spage %d of %d%s", colorGray, p.Page, p.Pages, colorReset)
+	}
+	fmt.Fprintln(w)
+	for i, location := range p.Locations {
+		fmt.Fprintf(w, "%s%2d.%s %s\n", colorGray, i+1, colorReset, location)
+	}
+	if p.hint != "" {
+		fmt.Fprintf(w, "\n%s%s%s\n", colorGray, p.hint, colorReset)
+	}
+	return nil
+}
+
+// RegionList lists every region
+type RegionList struct {
+	Regions []string `json:"regions"`
+}
+
+func (r RegionList) WriteTable(w io.Writer) error {
+	fmt.Fprintf(w, "%s═══ Regions ═══%s\n", colorCyan, colorReset)
+	for i, region := range r.Regions {
+		fmt.Fprintf(w, "%s%2d.%s %s\n", colorGray, i+1, colorReset, region)
+	}
+	fmt.Fprintf(w, "\n%sUse 'region <region_name>' to list its locations%s\n", colorGray, colorReset)
+	return nil
+}
+
+// RegionDetails lists the locations in a region
+type RegionDetails struct {
+	Name       string   `json:"name"`
+	Generation string   `json:"generation,omitempty"`
+	Locations  []string `json:"locations"`
+}
+
+func (r RegionDetails) WriteTable(w io.Writer) error {
+	fmt.Fprintf(w, "%s═══ Locations in %s ═══%s\n", colorCyan, r.Name, colorReset)
+	if r.Generation != "" {
+		fmt.Fprintf(w, "%sIntroduced in %s%s\n\n", colorGray, r.Generation, colorReset)
+	}
+	for i, location := range r.Locations {
+		fmt.Fprintf(w, "%s%3d.%s %s\n", colorGray, i+1, colorReset, location)
+	}
+	fmt.Fprintf(w, "\n%sUse 'location <location_name>' to list its areas%s\n", colorGray, colorReset)
+	return nil
+}
+
+// LocationDetails lists the areas in a location
+type LocationDetails struct {
+	Name   string   `json:"name"`
+	Region string   `json:"region,omitempty"`
+	Areas  []string `json:"areas"`
+}
+
+func (l LocationDetails) WriteTable(w io.Writer) error {
+	fmt.Fprintf(w, "%s═══ Areas in %s ═══%s\n", colorCyan, l.Name, colorReset)
+	if l.Region != "" {
+		fmt.Fprintf(w, "%sRegion: %s%s\n\n", colorGray, l.Region, colorReset)
+	}
+	if len(l.Areas) == 0 {
+		fmt.Fprintf(w, "%sThere are no areas to explore here%s\n", colorGray, colorReset)
+		return nil
+	}
+	for i, area := range l.Areas {
+		fmt.Fprintf(w, "%s%2d.%s %s\n", colorGray, i+1, colorReset, area)
+	}
+	fmt.Fprintf(w, "\n%sUse 'explore <area_name>' to see its Pokémon%s\n", colorGray, colorReset)
+	return nil
+}
+
+// AreaDetails lists the Pokémon found in an area
+type AreaDetails struct {
+	Area    string        `json:"area"`
+	Version string        `json:"version,omitempty"`
+	Pokemon []AreaPokemon `json:"pokemon"`
+	// detailed shows encounter details in the table, which only mean
+	// something once a version or realistic mode is chosen
+	detailed bool
+}
+
+// AreaPokemon is how a Pokémon shows up in an area
+type AreaPokemon struct {
+	Name     string   `json:"name"`
+	Chance   int      `json:"chance"`
+	MinLevel int      `json:"min_level"`
+	MaxLevel int      `json:"max_level"`
+	Methods  []string `json:"methods"`
+}
+
+func (a AreaDetails) WriteTable(w io.Writer) error {
+	if len(a.Pokemon) == 0 {
+		if a.Version != "" {
+			fmt.Fprintf(w, "%sNo Pokémon found in this area in %s%s\n", colorGray, a.Version, colorReset)
+		} else {
+			fmt.Fprintf(w, "%sNo Pokémon found in this area%s\n", colorGray, colorReset)
+		}
+		return nil
+	}
+
+	fmt.Fprintf(w, "\n%s═══ Pokémon Found in %s ═══%s\n", colorGreen, a.Area, colorReset)
+	for i, pokemon := range a.Pokemon {
+		if !a.detailed {
+			fmt.Fprintf(w, "%s%2d.%s %s\n", colorGray, i+1, colorReset, pokemon.Name)
+			continue
+		}
+		fmt.Fprintf(w, "%s%2d.%s %-20s %sLv. %s  %3d%%  %s%s\n", colorGray, i+1, colorReset, pokemon.Name,
+			colorGray, levelRange(pokemon.MinLevel, pokemon.MaxLevel), pokemon.Chance, strings.Join(pokemon.Methods, ", "), colorReset)
+	}
+	fmt.Fprintf(w, "\n%sUse 'catch <pokemon_name>' to attempt a catch!%s\n", colorGray, colorReset)
+	return nil
+}
+
+// Catch outcomes
+const (
+	outcomeCaught        = "caught"
+	outcomeBrokeFree     = "broke_free"
+	outcomeAlreadyCaught = "already_caught"
+	outcomeNotFound      = "not_found"
+)
+
+// CatchResult is how a catch attempt went
+type CatchResult struct {
+	Pokemon string `json:"pokemon"`
+	// Outcome is caught, broke_free, already_caught or not_found
+	Outcome        string  `json:"outcome"`
+	Area           string  `json:"area,omitempty"`
+	Ball           string  `json:"ball,omitempty"`
+	Level          int     `json:"level,omitempty"`
+	Shakes         int     `json:"shakes"`
+	Chance         float64 `json:"chance,omitempty"`
+	BaseExperience int     `json:"base_experience,omitempty"`
+}
+
+func (c CatchResult) WriteTable(w io.Writer) error {
+	switch c.Outcome {
+	case outcomeAlreadyCaught:
+		fmt.Fprintf(w, "%s✓ You've already caught %s!%s\n", colorYellow, c.Pokemon, colorReset)
+	case outcomeNotFound:
+		fmt.Fprintf(w, "%sYou searched %s, but no %s appeared. Try again!%s\n", colorGray, c.Area, c.Pokemon, colorReset)
+	case outcomeCaught:
+		fmt.Fprintf(w, "%s✓ Gotcha! %s was caught!%s\n", colorGreen, c.Pokemon, colorReset)
+		fmt.Fprintf(w, "  %sBase Experience: %d%s\n", colorGray, c.BaseExperience, colorReset)
+	default:
+		fmt.Fprintf(w, "%s✗ Oh no! %s broke free!%s\n", colorRed, c.Pokemon, colorReset)
+		fmt.Fprintf(w, "  %sCatch chance: %.1f%% - Try again!%s\n", colorGray, c.Chance*100, colorReset)
+	}
+	return nil
+}
+
+// PokemonDetails is a caught Pokémon's full record
+type PokemonDetails struct {
+	models.Pokemon
+}
+
+func (p PokemonDetails) WriteTable(w io.Writer) error {
+	pokemon := p.Pokemon
+
+	// Print header
+	title := strings.ToUpper(pokemon.Name)
+	if pokemon.Nickname != "" {
+		title = fmt.Sprintf("%s (%s)", pokemon.Nickname, title)
+	}
+	fmt.Fprintf(w, "\n%s╔═══════════════════════════════════╗%s\n", colorCyan, colorReset)
+	fmt.Fprintf(w, "%s║  %-31s  ║%s\n", colorCyan, title, colorReset)
+	fmt.Fprintf(w, "%s╚═══════════════════════════════════╝%s\n\n", colorCyan, colorReset)
+
+	// Basic info
+	fmt.Fprintf(w, "%sNo.:%s    %d\n", colorBold, colorReset, pokemon.ID)
+	fmt.Fprintf(w, "%sHeight:%s %d decimetres\n", colorBold, colorReset, pokemon.Height)
+	fmt.Fprintf(w, "%sWeight:%s %d hectograms\n", colorBold, colorReset, pokemon.Weight)
+	if pokemon.Sprite != "" {
+		fmt.Fprintf(w, "%sSprite:%s %s\n", colorBold, colorReset, pokemon.Sprite)
+	}
+	fmt.Fprintln(w)
+
+	// Types
+	fmt.Fprintf(w, "%sTypes:%s\n", colorBold, colorReset)
+	for _, typeName := range pokemon.Types {
+		typeColor := getTypeColor(typeName)
+		fmt.Fprintf(w, "  • %s%s%s\n", typeColor, typeName, colorReset)
+	}
+
+	// Abilities
+	fmt.Fprintf(w, "\n%sAbilities:%s\n", colorBold, colorReset)
+	for _, a := range pokemon.Abilities {
+		abilityName := strings.ReplaceAll(a.Name, "-", " ")
+		if a.IsHidden {
+			fmt.Fprintf(w, "  • %s %s(hidden)%s\n", abilityName, colorGray, colorReset)
+		} else {
+			fmt.Fprintf(w, "  • %s\n", abilityName)
+		}
+	}
+
+	// Stats with visual bars
+	fmt.Fprintf(w, "\n%sStats:%s\n", colorBold, colorReset)
+	for _, s := range pokemon.Stats {
+		statName := strings.ReplaceAll(s.Name, "-", " ")
+		bar := generateStatBar(s.BaseStat)
+		fmt.Fprintf(w, "  %-18s %s%3d%s %s\n", statName+":", colorGray, s.BaseStat, colorReset, bar)
+	}
+
+	// Catch details
+	if !pokemon.CaughtAt.IsZero() {
+		fmt.Fprintf(w, "\n%sCaught:%s %s", colorBold, colorReset, pokemon.CaughtAt.Format("2006-01-02 15:04"))
+		if pokemon.CaughtIn != "" {
+			fmt.Fprintf(w, " in %s", pokemon.CaughtIn)
+		}
+		if pokemon.Level > 0 {
+			fmt.Fprintf(w, " at Lv. %d", pokemon.Level)
+		}
+		fmt.Fprintln(w)
+		if pokemon.Ball != "" {
+			fmt.Fprintf(w, "  %sWith %s at %.1f%% odds%s\n", colorGray, ballDisplayName(pokemon.Ball), pokemon.CatchChance*100, colorReset)
+		}
+	}
+	return nil
+}
+
+// PokedexList lists every caught Pokémon, sorted by name
+type PokedexList struct {
+	Count   int              `json:"count"`
+	Pokemon []models.Pokemon `json:"pokemon"`
+}
+
+func (p PokedexList) WriteTable(w io.Writer) error {
+	if p.Count == 0 {
+		fmt.Fprintf(w, "%sYour Pokédex is empty!%s\n", colorYellow, colorReset)
+		fmt.Fprintf(w, "  %sUse 'explore' and 'catch' to start collecting Pokémon%s\n", colorGray, colorReset)
+		return nil
+	}
+
+	fmt.Fprintf(w, "%s╔═══════════════════════════════════╗%s\n", colorGreen, colorReset)
+	fmt.Fprintf(w, "%s║         YOUR POKÉDEX (%3d)        ║%s\n", colorGreen, p.Count, colorReset)
+	fmt.Fprintf(w, "%s╚═══════════════════════════════════╝%s\n\n", colorGreen, colorReset)
+
+	for i, pokemon := range p.Pokemon {
+		fmt.Fprintf(w, "%s%2d.%s %s", colorGray, i+1, colorReset, pokemon.DisplayName())
+		if pokemon.Nickname != "" {
+			fmt.Fprintf(w, " %s(%s)%s", colorGray, pokemon.Name, colorReset)
+		}
+		for _, typeName := range pokemon.Types {
+			fmt.Fprintf(w, " %s%s%s", getTypeColor(typeName), typeName, colorReset)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "\n%sUse 'inspect <pokemon_name>' to see details%s\n", colorGray, colorReset)
+	return nil
+}
+
+// Goodbye is printed when the session ends
+type Goodbye struct {
+	Caught int `json:"caught"`
+}
+
+func (g Goodbye) WriteTable(w io.Writer) error {
+	fmt.Fprintf(w, "\n%s╔═══════════════════════════════════╗%s\n", colorCyan, colorReset)
+	fmt.Fprintf(w, "%s║     Thanks for using Pokédex!    ║%s\n", colorCyan, colorReset)
+	fmt.Fprintf(w, "%s║      You caught %3d Pokémon       ║%s\n", colorCyan, g.Caught, colorReset)
+	fmt.Fprintf(w, "%s╚═══════════════════════════════════╝%s\n\n", colorCyan, colorReset)
+	return nil
+}
+
+// WalkResult is what a step in the current area turned up
+type WalkResult struct {
+	Area string `json:"area"`
+	// Method is empty when nothing can be encountered in the area
+	Method   string `json:"method,omitempty"`
+	Appeared bool   `json:"appeared"`
+	Pokemon  string `json:"pokemon,omitempty"`
+	Level    int    `json:"level,omitempty"`
+}
+
+func (r WalkResult) WriteTable(w io.Writer) error {
+	switch {
+	case r.Method == "":
+		fmt.Fprintf(w, "%sThere are no wild Pokémon in %s%s\n", colorGray, r.Area, colorReset)
+	case !r.Appeared:
+		fmt.Fprintf(w, "%sYou %s around %s... nothing appeared%s\n", colorGray, r.Method, r.Area, colorReset)
+	default:
+		fmt.Fprintf(w, "%sA wild %s (Lv. %d) appeared!%s\n", colorGreen, r.Pokemon, r.Level, colorReset)
+		fmt.Fprintf(w, "  %sUse 'catch' to throw a ball or 'flee' to run away%s\n", colorGray, colorReset)
+	}
+	return nil
+}
+
+// FleeResult is the wild Pokémon that was left behind
+type FleeResult struct {
+	Pokemon string `json:"pokemon"`
+}
+
+func (f FleeResult) WriteTable(w io.Writer) error {
+	fmt.Fprintf(w, "%sGot away safely from %s!%s\n", colorGreen, f.Pokemon, colorReset)
+	return nil
+}
+
+// ModeSetting is the catch mode, free or realistic
+type ModeSetting struct {
+	Mode string `json:"mode"`
+}
+
+func (m ModeSetting) WriteTable(w io.Writer) error {
+	fmt.Fprintf(w, "%sMode:%s %s\n", colorBold, colorReset, m.Mode)
+	if m.Mode == "realistic" {
+		fmt.Fprintf(w, "  %sOnly Pokémon found in the explored area can be caught, and they have to show up first%s\n", colorGray, colorReset)
+	} else {
+		fmt.Fprintf(w, "  %sAny Pokémon can be caught from anywhere%s\n", colorGray, colorReset)
+	}
+	return nil
+}
+
+// VersionSetting is the game version encounters are rolled for
+type VersionSetting struct {
+	// Version is "any" when no version is chosen
+	Version string `json:"version"`
+	changed bool
+}
+
+func (v VersionSetting) WriteTable(w io.Writer) error {
+	switch {
+	case v.changed && v.Version == "any":
+		fmt.Fprintf(w, "%s✓ Encounters from every version%s\n", colorGreen, colorReset)
+	case v.changed:
+		fmt.Fprintf(w, "%s✓ Playing %s%s\n", colorGreen, v.Version, colorReset)
+	case v.Version == "any":
+		fmt.Fprintf(w, "%sVersion:%s any\n", colorBold, colorReset)
+		fmt.Fprintf(w, "  %sUse 'version <name>' to pick a game, like red or heartgold%s\n", colorGray, colorReset)
+	default:
+		fmt.Fprintf(w, "%sVersion:%s %s\n", colorBold, colorReset, v.Version)
+	}
+	return nil
+}
+
+// SeedSetting is the seed of the session's random source
+type SeedSetting struct {
+	Seed    uint64 `json:"seed"`
+	changed bool
+}
+
+func (s SeedSetting) WriteTable(w io.Writer) error {
+	if s.changed {
+		fmt.Fprintf(w, "%s✓ Seed set to %d%s\n", colorGreen, s.Seed, colorReset)
+		return nil
+	}
+	fmt.Fprintf(w, "%sSeed:%s %d\n", colorBold, colorReset, s.Seed)
+	fmt.Fprintf(w, "  %sRun with --seed %d to replay this session%s\n", colorGray, s.Seed, colorReset)
+	return nil
+}
+
+// CacheStats is a snapshot of the response cache
+type CacheStats struct {
+	pokecache.Stats
+	HitRate float64 `json:"hit_rate"`
+}
+
+func (c CacheStats) WriteTable(w io.Writer) error {
+	stats := c.Stats
+	fmt.Fprintf(w, "%s═══ Cache ═══%s\n", colorCyan, colorReset)
+	fmt.Fprintf(w, "%sEntries:%s   %d (%s)\n", colorBold, colorReset, stats.Entries, formatBytes(stats.Bytes))
+	fmt.Fprintf(w, "%sHits:%s      %d\n", colorBold, colorReset, stats.Hits)
+	fmt.Fprintf(w, "%sMisses:%s    %d\n", colorBold, colorReset, stats.Misses)
+	fmt.Fprintf(w, "%sHit rate:%s  %.1f%%\n", colorBold, colorReset, c.HitRate)
+	fmt.Fprintf(w, "%sEvictions:%s %d\n", colorBold, colorReset, stats.Evictions)
+	fmt.Fprintf(w, "%sExpired:%s   %d\n", colorBold, colorReset, stats.Expired)
+
+	if len(stats.Groups) == 0 {
+		return nil
+	}
+	groups := make([]string, 0, len(stats.Groups))
+	for name := range stats.Groups {
+		groups = append(groups, name)
+	}
+	sort.Strings(groups)
+
+	fmt.Fprintf(w, "\n%s%-16s %8s %10s %6s %6s %6s%s\n", colorGray, "resource", "entries", "size", "hits", "misses", "evict", colorReset)
+	for _, name := range groups {
+		g := stats.Groups[name]
+		fmt.Fprintf(w, "%-16s %8d %10s %6d %6d %6d\n", name, g.Entries, formatBytes(g.Bytes), g.Hits, g.Misses, g.Evictions+g.Expired)
+	}
+	return nil
+}
+
+// CacheKeys lists the cached URLs, most recently used first
+type CacheKeys struct {
+	URLs []string `json:"urls"`
+}
+
+func (c CacheKeys) WriteTable(w io.Writer) error {
+	if len(c.URLs) == 0 {
+		fmt.Fprintf(w, "%sThe cache is empty%s\n", colorGray, colorReset)
+		return nil
+	}
+	fmt.Fprintf(w, "%s═══ Cached URLs ═══%s\n", colorCyan, colorReset)
+	for i, key := range c.URLs {
+		fmt.Fprintf(w, "%s%3d.%s %s\n", colorGray, i+1, colorReset, key)
+	}
+	return nil
+}
+
+// CommandList is the help text, grouped by category
+type CommandList struct {
+	Groups []CommandGroup `json:"groups"`
+}
+
+// CommandGroup is one category of commands
+type CommandGroup struct {
+	Title    string        `json:"title"`
+	Commands []CommandInfo `json:"commands"`
+}
+
+// CommandInfo describes a single command
+type CommandInfo struct {
+	Name        string `json:"name"`
+	Usage       string `json:"usage"`
+	Description string `json:"description"`
+}
+
+func (c CommandList) WriteTable(w io.Writer) error {
+	fmt.Fprintf(w, "%s╔═══════════════════════════════════════════════════════════╗%s\n", colorCyan, colorReset)
+	fmt.Fprintf(w, "%s║                  POKÉDEX COMMANDS                         ║%s\n", colorCyan, colorReset)
+	fmt.Fprintf(w, "%s╚═══════════════════════════════════════════════════════════╝%s\n\n", colorCyan, colorReset)
+
+	for _, group := range c.Groups {
+		fmt.Fprintf(w, "%s%s:%s\n", colorBold, group.Title, colorReset)
+		for _, cmd := range group.Commands {
+			// A usage too long for the column gets a line of its own
+			if len(cmd.Usage) > 25 {
+				fmt.Fprintf(w, "  %s%s%s\n  %-25s", colorGreen, cmd.Usage, colorReset, "")
+			} else {
+				fmt.Fprintf(w, "  %s%-25s%s", colorGreen, cmd.Usage, colorReset)
+			}
+			fmt.Fprintf(w, " %s\n", cmd.Description)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
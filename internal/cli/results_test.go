@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"pokedexcli/internal/output"
+	"testing"
+)
+
+func TestResultsJSON(t *testing.T) {
+	cases := []struct {
+		name     string
+		result   any
+		expected string
+	}{
+		{
+			name:     "unexported fields are left out",
+			result:   LocationPage{Page: 2, Pages: 3, Count: 45, Locations: []string{"canalave-city-area"}, hint: "Type 'map' for more locations"},
+			expected: `{"page":2,"pages":3,"count":45,"locations":["canalave-city-area"]}`,
+		},
+		{
+			name:     "a catch that broke free",
+			result:   CatchResult{Pokemon: "pikachu", Outcome: outcomeBrokeFree, Ball: "poke-ball", Shakes: 2, Chance: 0.5, BaseExperience: 112},
+			expected: `{"pokemon":"pikachu","outcome":"broke_free","ball":"poke-ball","shakes":2,"chance":0.5,"base_experience":112}`,
+		},
+		{
+			name:     "a walk that found nothing",
+			result:   WalkResult{Area: "viridian-forest-area", Method: "walk"},
+			expected: `{"area":"viridian-forest-area","method":"walk","appeared":false}`,
+		},
+		{
+			name:     "any version",
+			result:   versionSetting("", true),
+			expected: `{"version":"any"}`,
+		},
+	}
+	for _, c := range cases {
+		var b bytes.Buffer
+		if err := (output.JSON{}).Format(&b, c.result); err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, b.Bytes()); err != nil {
+			t.Fatalf("%s: invalid JSON %q: %v", c.name, b.String(), err)
+		}
+		if compact.String() != c.expected {
+			t.Errorf("%s: got %s, expected %s", c.name, compact.String(), c.expected)
+		}
+	}
+}
+
+func TestMachineOutputSkipsAnimation(t *testing.T) {
+	config, fake := newTestConfig(t, 1)
+	config.Output = output.JSON{}
+	start := fake.Now()
+
+	if err := CommandCatch(context.Background(), discard, config, []string{"pikachu", "--ball", "master"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pokemon, ok := config.Pokedex["pikachu"]
+	if !ok {
+		t.Fatalf("a master ball should always catch")
+	}
+	if !pokemon.CaughtAt.Equal(start) {
+		t.Errorf("CaughtAt == %v, expected no time to pass", pokemon.CaughtAt)
+	}
+}
+
+func TestInspectUncaught(t *testing.T) {
+	config, _ := newTestConfig(t, 1)
+	if err := CommandInspect(context.Background(), discard, config, []string{"pikachu"}); err == nil {
+		t.Errorf("expected an error inspecting a Pokémon that wasn't caught")
+	}
+}
@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"pokedexcli/internal/lineedit"
+	"pokedexcli/internal/models"
+	"pokedexcli/internal/storage"
+	"pokedexcli/internal/theme"
+	"strings"
+	"testing"
+)
+
+func TestRunScript(t *testing.T) {
+	cases := []struct {
+		name     string
+		script   string
+		expected int
+		caught   bool
+	}{
+		{
+			name:     "commands and comments",
+			script:   "# catch one\n\nseed 7\ncatch pikachu --ball master\npokedex\n",
+			expected: exitOK,
+			caught:   true,
+		},
+		{
+			name:     "stops at the first error",
+			script:   "catch mewtwo\ncatch pikachu --ball master\n",
+			expected: exitError,
+		},
+		{
+			name:     "unknown command",
+			script:   "throw pikachu\n",
+			expected: exitUsage,
+		},
+		{
+			name:     "exit ends the script",
+			script:   "exit\ncatch pikachu --ball master\n",
+			expected: exitOK,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config, _ := newTestConfig(t, 1)
+			code := runScript(&interruptHandler{}, discard, config, strings.NewReader(c.script))
+			if code != c.expected {
+				t.Errorf("exit code %d, expected %d", code, c.expected)
+			}
+			if _, caught := config.Pokedex["pikachu"]; caught != c.caught {
+				t.Errorf("caught pikachu == %v, expected %v", caught, c.caught)
+			}
+		})
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	config, _ := newTestConfig(t, 1)
+	interrupts := &interruptHandler{}
+
+	if code := runCommand(interrupts, discard, config, CleanInput("CATCH pikachu --ball master")); code != exitOK {
+		t.Errorf("exit code %d, expected %d", code, exitOK)
+	}
+	if _, caught := config.Pokedex["pikachu"]; !caught {
+		t.Errorf("expected pikachu to be caught")
+	}
+	if code := runCommand(interrupts, discard, config, []string{"inspect"}); code != exitError {
+		t.Errorf("exit code %d for a usage error, expected %d", code, exitError)
+	}
+}
+
+func TestRunSavesSession(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pokedex.json")
+	// session starts a run that restores the session saved at path
+	session := func() *models.ReplConfig {
+		config, _ := newTestConfig(t, 1)
+		config.Store = storage.NewStore(path)
+		if err := config.Store.Load(config); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return config
+	}
+
+	// map doesn't save by itself, but the cursor must survive the run
+	if code := runCommand(&interruptHandler{}, discard, session(), []string{"map"}); code != exitOK {
+		t.Fatalf("exit code %d, expected %d", code, exitOK)
+	}
+	streams, out, _ := newTestStreams()
+	if code := runCommand(&interruptHandler{}, streams, session(), []string{"map"}); code != exitOK {
+		t.Fatalf("exit code %d, expected %d", code, exitOK)
+	}
+	if !strings.Contains(out.String(), "area-20") {
+		t.Errorf("expected the second run to show the next page, got:\n%s", out.String())
+	}
+
+	// A script that ends without exit is saved too
+	if code := runScript(&interruptHandler{}, discard, session(), strings.NewReader("map first\n")); code != exitOK {
+		t.Fatalf("exit code %d, expected %d", code, exitOK)
+	}
+	if config := session(); config.Next == "" {
+		t.Errorf("expected the script's map cursor to be saved")
+	}
+
+	config := session()
+	config.Store = failingStore{}
+	if code := runCommand(&interruptHandler{}, discard, config, []string{"map"}); code != exitError {
+		t.Errorf("exit code %d when saving fails, expected %d", code, exitError)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected int
+	}{
+		{err: nil, expected: exitOK},
+		{err: ErrExit, expected: exitOK},
+		{err: fmt.Errorf("line 3: %w", context.Canceled), expected: exitInterrupted},
+		{err: &unknownCommandError{command: "throw"}, expected: exitUsage},
+		{err: errors.New("no Pokémon named 'pikachuu'"), expected: exitError},
+	}
+	for _, c := range cases {
+		if code := exitCode(c.err); code != c.expected {
+			t.Errorf("exitCode(%v) == %d, expected %d", c.err, code, c.expected)
+		}
+	}
+}
+
+func TestStartREPL(t *testing.T) {
+	config, _ := newTestConfig(t, 1)
+	streams, out, errOut := newTestStreams()
+	editor := lineedit.New(strings.NewReader("catch pikachu --ball master\nthrow pikachu\ninspect\n"), out)
+
+	// The input ends without exit, like Ctrl-D
+	if code := startREPL(&interruptHandler{}, streams, config, editor); code != exitOK {
+		t.Errorf("exit code %d, expected %d", code, exitOK)
+	}
+	for _, expected := range []string{"COMMAND LINE INTERFACE", "[0 caught]", "Gotcha! pikachu was caught!", "[1 caught]", "You caught   1 Pokémon"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q in output:\n%s", expected, out.String())
+		}
+	}
+	for _, expected := range []string{"Unknown command 'throw'", "usage: inspect <pokemon_name>"} {
+		if !strings.Contains(errOut.String(), expected) {
+			t.Errorf("expected %q in errors:\n%s", expected, errOut.String())
+		}
+	}
+}
+
+func TestREPLLogHasNoEscapes(t *testing.T) {
+	// Colors are off when stdout isn't a terminal
+	usePalette(theme.Palette{})
+	t.Cleanup(func() {
+		palette, _ := theme.Default().Palette(theme.Basic)
+		usePalette(palette)
+	})
+
+	// Like pokedexcli > session.log
+	log, err := os.Create(filepath.Join(t.TempDir(), "session.log"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer log.Close()
+	config, _ := newTestConfig(t, 1)
+	streams := Streams{Out: log, Err: io.Discard}
+	editor := lineedit.New(strings.NewReader("catch pikachu --ball master\n"), log)
+	if code := startREPL(&interruptHandler{}, streams, config, editor); code != exitOK {
+		t.Errorf("exit code %d, expected %d", code, exitOK)
+	}
+
+	data, err := os.ReadFile(log.Name())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), "pikachu was caught") {
+		t.Errorf("expected the session in the log, got:\n%s", data)
+	}
+	if strings.Contains(string(data), "\033[") {
+		t.Errorf("expected no escape sequences in the log, got:\n%q", data)
+	}
+}
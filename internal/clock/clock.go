@@ -0,0 +1,72 @@
+// Package clock abstracts time so game mechanics can be replayed and tested.
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and waits
+type Clock interface {
+	Now() time.Time
+	// Sleep waits for d, returning early with ctx's error if ctx ends
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// Real is the system clock
+type Real struct{}
+
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+func (Real) Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Fake is a clock that only moves when slept on, so sleeps return
+// immediately
+type Fake struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+// NewFake returns a fake clock set to now
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.now
+}
+
+// Sleep advances the clock by d without waiting
+func (f *Fake) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.now = f.now.Add(d)
+	return nil
+}
+
+// NoSleep is the system clock without the waiting, for when nobody is
+// watching the animations
+type NoSleep struct {
+	Real
+}
+
+func (NoSleep) Sleep(ctx context.Context, d time.Duration) error {
+	return ctx.Err()
+}
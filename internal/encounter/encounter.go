@@ -0,0 +1,217 @@
+// Package encounter works out which wild Pokémon appear in a location area,
+// how often and at what level.
+package encounter
+
+import (
+	"pokedexcli/internal/pokeapi"
+	"slices"
+)
+
+// Slot is one way a Pokémon can be encountered in an area
+type Slot struct {
+	Pokemon string
+	Version string
+	Method  string
+	// Chance is the percent chance this slot is picked when the method is used
+	Chance   int
+	MinLevel int
+	MaxLevel int
+	// MaxChance is the Pokémon's total chance to appear in this version
+	MaxChance int
+}
+
+// Rate is how often a method finds anything at all in a version
+type Rate struct {
+	Method  string
+	Version string
+	// Rate is the percent chance of an encounter per step
+	Rate int
+}
+
+// Encounter is a wild Pokémon that has appeared
+type Encounter struct {
+	Pokemon string
+	Method  string
+	Level   int
+}
+
+// Summary describes how a Pokémon shows up in an area
+type Summary struct {
+	Pokemon  string
+	Chance   int
+	MinLevel int
+	MaxLevel int
+	Methods  []string
+}
+
+// Table holds the encounter slots of a location area
+type Table struct {
+	Area  string
+	Slots []Slot
+	Rates []Rate
+}
+
+// NewTable builds the encounter table of a location area
+func NewTable(area pokeapi.LocationAreasDetailsResponse) Table {
+	table := Table{Area: area.Name}
+	for _, methodRate := range area.EncounterMethodRates {
+		for _, versionDetail := range methodRate.VersionDetails {
+			table.Rates = append(table.Rates, Rate{
+				Method:  methodRate.EncounterMethod.Name,
+				Version: versionDetail.Version.Name,
+				Rate:    versionDetail.Rate,
+			})
+		}
+	}
+	for _, pokemonEncounter := range area.PokemonEncounters {
+		for _, versionDetail := range pokemonEncounter.VersionDetails {
+			for _, detail := range versionDetail.EncounterDetails {
+				table.Slots = append(table.Slots, Slot{
+					Pokemon:   pokemonEncounter.Pokemon.Name,
+					Version:   versionDetail.Version.Name,
+					Method:    detail.Method.Name,
+					Chance:    detail.Chance,
+					MinLevel:  detail.MinLevel,
+					MaxLevel:  detail.MaxLevel,
+					MaxChance: versionDetail.MaxChance,
+				})
+			}
+		}
+	}
+	return table
+}
+
+// Pokemon lists the Pokémon found in the area in version, or in any version
+// if version is empty, in the order PokeAPI gave them
+func (t Table) Pokemon(version string) []string {
+	var names []string
+	for _, slot := range t.Slots {
+		if matches(slot.Version, version) && !slices.Contains(names, slot.Pokemon) {
+			names = append(names, slot.Pokemon)
+		}
+	}
+	return names
+}
+
+// Has reports whether name can be encountered in the area in version
+func (t Table) Has(name, version string) bool {
+	return len(t.slotsFor(name, version)) > 0
+}
+
+// Summarize combines every slot of name in version into one description
+func (t Table) Summarize(name, version string) (Summary, bool) {
+	slots := t.slotsFor(name, version)
+	if len(slots) == 0 {
+		return Summary{}, false
+	}
+	summary := Summary{Pokemon: name, MinLevel: slots[0].MinLevel, MaxLevel: slots[0].MaxLevel}
+	for _, slot := range slots {
+		summary.Chance = max(summary.Chance, slot.MaxChance)
+		summary.MinLevel = min(summary.MinLevel, slot.MinLevel)
+		summary.MaxLevel = max(summary.MaxLevel, slot.MaxLevel)
+		if !slices.Contains(summary.Methods, slot.Method) {
+			summary.Methods = append(summary.Methods, slot.Method)
+		}
+	}
+	return summary, true
+}
+
+// Search looks for name in the tall grass. It appears with its area chance,
+// through a slot picked by slot chance, at a level within that slot's range.
+// intn returns a random number in [0, n), like rand.IntN.
+func (t Table) Search(name, version string, intn func(int) int) (Encounter, bool) {
+	summary, ok := t.Summarize(name, version)
+	if !ok || intn(100) >= summary.Chance {
+		return Encounter{}, false
+	}
+	slot := pickSlot(t.slotsFor(name, version), intn)
+	return Encounter{Pokemon: name, Method: slot.Method, Level: level(slot, intn)}, true
+}
+
+// Methods lists the encounter methods usable in version, or in any version
+// if version is empty
+func (t Table) Methods(version string) []string {
+	var methods []string
+	for _, rate := range t.Rates {
+		if matches(rate.Version, version) && !slices.Contains(methods, rate.Method) {
+			methods = append(methods, rate.Method)
+		}
+	}
+	return methods
+}
+
+// Rate returns the chance per step that method finds a Pokémon in version.
+// With an empty version the best rate of any version is used.
+func (t Table) Rate(method, version string) int {
+	best := 0
+	for _, rate := range t.Rates {
+		if rate.Method == method && matches(rate.Version, version) {
+			best = max(best, rate.Rate)
+		}
+	}
+	return best
+}
+
+// Step takes one step using method. A Pokémon appears with the method's
+// rate, picked from the method's slots by chance, at a level within that
+// slot's range.
+func (t Table) Step(method, version string, intn func(int) int) (Encounter, bool) {
+	if intn(100) >= t.Rate(method, version) {
+		return Encounter{}, false
+	}
+	var slots []Slot
+	for _, slot := range t.Slots {
+		if slot.Method == method && matches(slot.Version, version) {
+			slots = append(slots, slot)
+		}
+	}
+	if len(slots) == 0 {
+		return Encounter{}, false
+	}
+	slot := pickSlot(slots, intn)
+	return Encounter{Pokemon: slot.Pokemon, Method: method, Level: level(slot, intn)}, true
+}
+
+// matches reports whether a version matches the wanted one, where an empty
+// wanted version matches all of them
+func matches(version, wanted string) bool {
+	return wanted == "" || version == wanted
+}
+
+func (t Table) slotsFor(name, version string) []Slot {
+	var slots []Slot
+	for _, slot := range t.Slots {
+		if slot.Pokemon == name && matches(slot.Version, version) {
+			slots = append(slots, slot)
+		}
+	}
+	return slots
+}
+
+// pickSlot picks a slot weighted by its chance, or uniformly if no slot
+// has one
+func pickSlot(slots []Slot, intn func(int) int) Slot {
+	total := 0
+	for _, slot := range slots {
+		total += slot.Chance
+	}
+	if total <= 0 {
+		return slots[intn(len(slots))]
+	}
+	roll := intn(total)
+	for _, slot := range slots {
+		if roll < slot.Chance {
+			return slot
+		}
+		roll -= slot.Chance
+	}
+	return slots[len(slots)-1]
+}
+
+// level picks a level uniformly from the slot's range
+func level(slot Slot, intn func(int) int) int {
+	if slot.MaxLevel <= slot.MinLevel {
+		return slot.MinLevel
+	}
+	return slot.MinLevel + intn(slot.MaxLevel-slot.MinLevel+1)
+}
@@ -0,0 +1,220 @@
+package encounter
+
+import (
+	"encoding/json"
+	"pokedexcli/internal/pokeapi"
+	"slices"
+	"testing"
+)
+
+const areaJSON = `{
+	"name": "viridian-forest-area",
+	"encounter_method_rates": [
+		{
+			"encounter_method": {"name": "walk"},
+			"version_details": [{"rate": 25, "version": {"name": "red"}}, {"rate": 30, "version": {"name": "yellow"}}]
+		},
+		{
+			"encounter_method": {"name": "surf"},
+			"version_details": [{"rate": 10, "version": {"name": "yellow"}}]
+		}
+	],
+	"pokemon_encounters": [
+		{
+			"pokemon": {"name": "caterpie"},
+			"version_details": [
+				{
+					"version": {"name": "red"},
+					"max_chance": 50,
+					"encounter_details": [
+						{"min_level": 3, "max_level": 5, "chance": 40, "method": {"name": "walk"}},
+						{"min_level": 4, "max_level": 4, "chance": 10, "method": {"name": "walk"}}
+					]
+				}
+			]
+		},
+		{
+			"pokemon": {"name": "pikachu"},
+			"version_details": [
+				{
+					"version": {"name": "red"},
+					"max_chance": 5,
+					"encounter_details": [
+						{"min_level": 3, "max_level": 5, "chance": 5, "method": {"name": "walk"}}
+					]
+				},
+				{
+					"version": {"name": "yellow"},
+					"max_chance": 10,
+					"encounter_details": [
+						{"min_level": 6, "max_level": 8, "chance": 10, "method": {"name": "surf"}}
+					]
+				}
+			]
+		}
+	]
+}`
+
+func newTestTable(t *testing.T) Table {
+	t.Helper()
+	var area pokeapi.LocationAreasDetailsResponse
+	if err := json.Unmarshal([]byte(areaJSON), &area); err != nil {
+		t.Fatal(err)
+	}
+	return NewTable(area)
+}
+
+// fixed returns an intn that always rolls the given fraction of n
+func fixed(fraction float64) func(int) int {
+	return func(n int) int {
+		return int(fraction * float64(n))
+	}
+}
+
+func TestNewTable(t *testing.T) {
+	table := newTestTable(t)
+	if table.Area != "viridian-forest-area" {
+		t.Errorf("Area == %q", table.Area)
+	}
+	if len(table.Slots) != 4 {
+		t.Errorf("expected 4 slots, got %d", len(table.Slots))
+	}
+	if names := table.Pokemon(""); !slices.Equal(names, []string{"caterpie", "pikachu"}) {
+		t.Errorf("Pokemon(\"\") == %v", names)
+	}
+	if names := table.Pokemon("yellow"); !slices.Equal(names, []string{"pikachu"}) {
+		t.Errorf("Pokemon(\"yellow\") == %v", names)
+	}
+	if !table.Has("pikachu", "") || table.Has("mewtwo", "") || table.Has("caterpie", "yellow") {
+		t.Errorf("Has reported the wrong Pokémon")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	table := newTestTable(t)
+	summary, ok := table.Summarize("pikachu", "")
+	if !ok {
+		t.Fatalf("expected pikachu to be found")
+	}
+	if summary.Chance != 10 || summary.MinLevel != 3 || summary.MaxLevel != 8 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	if !slices.Equal(summary.Methods, []string{"walk", "surf"}) {
+		t.Errorf("Methods == %v", summary.Methods)
+	}
+	if _, ok := table.Summarize("mewtwo", ""); ok {
+		t.Errorf("mewtwo should not be found")
+	}
+
+	// Only the red slots count in red
+	summary, _ = table.Summarize("pikachu", "red")
+	if summary.Chance != 5 || summary.MinLevel != 3 || summary.MaxLevel != 5 {
+		t.Errorf("unexpected red summary: %+v", summary)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	table := newTestTable(t)
+	cases := []struct {
+		name     string
+		pokemon  string
+		version  string
+		roll     float64
+		found    bool
+		expected Encounter
+	}{
+		{name: "low roll finds", pokemon: "caterpie", roll: 0, found: true, expected: Encounter{Pokemon: "caterpie", Method: "walk", Level: 3}},
+		{name: "high roll misses", pokemon: "caterpie", roll: 0.6, found: false},
+		{name: "rare pokemon misses", pokemon: "pikachu", roll: 0.2, found: false},
+		{name: "absent pokemon", pokemon: "mewtwo", roll: 0, found: false},
+		{name: "absent in version", pokemon: "caterpie", version: "yellow", roll: 0, found: false},
+		{name: "version slots only", pokemon: "pikachu", version: "yellow", roll: 0, found: true, expected: Encounter{Pokemon: "pikachu", Method: "surf", Level: 6}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			encounter, found := table.Search(c.pokemon, c.version, fixed(c.roll))
+			if found != c.found {
+				t.Fatalf("found == %v, expected %v", found, c.found)
+			}
+			if encounter != c.expected {
+				t.Errorf("encounter == %+v, expected %+v", encounter, c.expected)
+			}
+		})
+	}
+}
+
+func TestPickSlotWeighting(t *testing.T) {
+	slots := []Slot{{Method: "walk", Chance: 40}, {Method: "surf", Chance: 10}}
+	counts := map[string]int{}
+	for roll := 0; roll < 50; roll++ {
+		counts[pickSlot(slots, func(int) int { return roll }).Method]++
+	}
+	if counts["walk"] != 40 || counts["surf"] != 10 {
+		t.Errorf("unexpected slot counts: %v", counts)
+	}
+}
+
+func TestLevelInRange(t *testing.T) {
+	slot := Slot{MinLevel: 3, MaxLevel: 5}
+	for roll := 0; roll < 3; roll++ {
+		if l := level(slot, func(int) int { return roll }); l != 3+roll {
+			t.Errorf("roll %d gave level %d", roll, l)
+		}
+	}
+	if l := level(Slot{MinLevel: 7, MaxLevel: 7}, fixed(0.9)); l != 7 {
+		t.Errorf("fixed level slot gave %d", l)
+	}
+}
+
+func TestRates(t *testing.T) {
+	table := newTestTable(t)
+	if methods := table.Methods(""); !slices.Equal(methods, []string{"walk", "surf"}) {
+		t.Errorf("Methods(\"\") == %v", methods)
+	}
+	if methods := table.Methods("red"); !slices.Equal(methods, []string{"walk"}) {
+		t.Errorf("Methods(\"red\") == %v", methods)
+	}
+	cases := []struct {
+		method   string
+		version  string
+		expected int
+	}{
+		{method: "walk", version: "red", expected: 25},
+		{method: "walk", version: "", expected: 30},
+		{method: "surf", version: "red", expected: 0},
+		{method: "old-rod", version: "", expected: 0},
+	}
+	for _, c := range cases {
+		if rate := table.Rate(c.method, c.version); rate != c.expected {
+			t.Errorf("Rate(%q, %q) == %d, expected %d", c.method, c.version, rate, c.expected)
+		}
+	}
+}
+
+func TestStep(t *testing.T) {
+	table := newTestTable(t)
+	cases := []struct {
+		name     string
+		method   string
+		version  string
+		roll     float64
+		found    bool
+		expected Encounter
+	}{
+		{name: "nothing appears", method: "walk", version: "red", roll: 0.5, found: false},
+		{name: "first walk slot", method: "walk", version: "red", roll: 0, found: true, expected: Encounter{Pokemon: "caterpie", Method: "walk", Level: 3}},
+		{name: "surf only in yellow", method: "surf", version: "red", roll: 0, found: false},
+		{name: "surf", method: "surf", version: "yellow", roll: 0, found: true, expected: Encounter{Pokemon: "pikachu", Method: "surf", Level: 6}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			encounter, found := table.Step(c.method, c.version, fixed(c.roll))
+			if found != c.found {
+				t.Fatalf("found == %v, expected %v", found, c.found)
+			}
+			if encounter != c.expected {
+				t.Errorf("encounter == %+v, expected %+v", encounter, c.expected)
+			}
+		})
+	}
+}
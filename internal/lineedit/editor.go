@@ -0,0 +1,344 @@
+// Package lineedit reads lines from a terminal with editing, history,
+// reverse search (Ctrl-R) and tab completion. When the input or output
+// isn't a terminal it reads plain lines instead.
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInterrupted is returned by ReadLine when Ctrl-C is pressed
+var ErrInterrupted = errors.New("interrupted")
+
+// Completer returns the completions of the word ending at the end of
+// line, which is the text before the cursor. start is the byte offset in
+// line where that word begins, and each candidate replaces it.
+type Completer func(line string) (start int, candidates []string)
+
+// Editor reads lines typed by the user
+type Editor struct {
+	// History is searched with the arrow keys and Ctrl-R; ReadLine adds
+	// each line it returns
+	History *History
+	// Complete is called on Tab, if set
+	Complete Completer
+
+	in  *bufio.Reader
+	out io.Writer
+	// fd is the terminal being read, or -1 for plain input
+	fd int
+}
+
+// New returns an editor reading from in and echoing to out. Editing is
+// only available when both are terminals, so redrawing the line never
+// sends escape sequences to a file or a pipe.
+func New(in io.Reader, out io.Writer) *Editor {
+	e := &Editor{History: NewHistory(0), in: bufio.NewReader(in), out: out, fd: -1}
+	if terminal(in) && terminal(out) {
+		e.fd = int(in.(*os.File).Fd())
+	}
+	return e
+}
+
+// terminal reports whether v is a file open on a terminal
+func terminal(v any) bool {
+	f, ok := v.(*os.File)
+	return ok && isTerminal(int(f.Fd()))
+}
+
+// ReadLine shows prompt and returns the line typed, without the newline.
+// It returns io.EOF on Ctrl-D at an empty line or the end of input, and
+// ErrInterrupted on Ctrl-C. If the line can't be saved to the history
+// file it is returned along with the error.
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	var line string
+	var err error
+	restore, rawErr := e.raw()
+	if rawErr == nil {
+		line, err = e.edit(prompt)
+		restore()
+	} else {
+		line, err = e.readPlain(prompt)
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := e.History.Add(line); err != nil {
+		return line, err
+	}
+	return line, nil
+}
+
+func (e *Editor) raw() (func() error, error) {
+	if e.fd < 0 {
+		return nil, errors.New("not a terminal")
+	}
+	return makeRaw(e.fd)
+}
+
+// readPlain reads a line as the terminal sends it
+func (e *Editor) readPlain(prompt string) (string, error) {
+	fmt.Fprint(e.out, prompt)
+	line, err := e.in.ReadString('\n')
+	if errors.Is(err, io.EOF) && line != "" {
+		err = nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// buffer is the line being edited
+type buffer struct {
+	prompt string
+	line   []rune
+	pos    int
+}
+
+func (b *buffer) insert(text []rune) {
+	b.line = append(b.line[:b.pos], append(text, b.line[b.pos:]...)...)
+	b.pos += len(text)
+}
+
+// set replaces the line and moves the cursor to its end
+func (b *buffer) set(line string) {
+	b.line = []rune(line)
+	b.pos = len(b.line)
+}
+
+// deleteBack removes the runes from start up to the cursor
+func (b *buffer) deleteBack(start int) {
+	b.line = append(b.line[:start], b.line[b.pos:]...)
+	b.pos = start
+}
+
+// wordStart returns where the word before the cursor begins
+func (b *buffer) wordStart() int {
+	start := b.pos
+	for start > 0 && b.line[start-1] == ' ' {
+		start--
+	}
+	for start > 0 && b.line[start-1] != ' ' {
+		start--
+	}
+	return start
+}
+
+// edit reads a line key by key, with the terminal in raw mode
+func (e *Editor) edit(prompt string) (string, error) {
+	b := &buffer{prompt: prompt}
+	// browsing is the history entry shown, History.Len() for the new line,
+	// whose text is kept in draft while browsing
+	browsing := e.History.Len()
+	draft := ""
+	e.refresh(b)
+
+	for {
+		k, err := readKey(e.in)
+		if err != nil {
+			return "", err
+		}
+
+		switch k {
+		case enter, ctrlJ:
+			fmt.Fprint(e.out, "\r\n")
+			return string(b.line), nil
+		case ctrlC:
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", ErrInterrupted
+		case ctrlD:
+			if len(b.line) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			if b.pos < len(b.line) {
+				b.pos++
+				b.deleteBack(b.pos - 1)
+			}
+		case backspace, ctrlH:
+			if b.pos > 0 {
+				b.deleteBack(b.pos - 1)
+			}
+		case keyDelete:
+			if b.pos < len(b.line) {
+				b.pos++
+				b.deleteBack(b.pos - 1)
+			}
+		case keyLeft, ctrlB:
+			if b.pos > 0 {
+				b.pos--
+			}
+		case keyRight, ctrlF:
+			if b.pos < len(b.line) {
+				b.pos++
+			}
+		case keyHome, ctrlA:
+			b.pos = 0
+		case keyEnd, ctrlE:
+			b.pos = len(b.line)
+		case ctrlU:
+			b.deleteBack(0)
+		case ctrlK:
+			b.line = b.line[:b.pos]
+		case ctrlW:
+			b.deleteBack(b.wordStart())
+		case ctrlL:
+			fmt.Fprint(e.out, "\033[H\033[2J")
+		case keyUp, ctrlP:
+			if browsing > 0 {
+				if browsing == e.History.Len() {
+					draft = string(b.line)
+				}
+				browsing--
+				b.set(e.History.At(browsing))
+			}
+		case keyDown, ctrlN:
+			if browsing < e.History.Len() {
+				browsing++
+				if browsing == e.History.Len() {
+					b.set(draft)
+				} else {
+					b.set(e.History.At(browsing))
+				}
+			}
+		case ctrlR:
+			line, done, err := e.search(b)
+			if err != nil {
+				return "", err
+			}
+			if done {
+				return line, nil
+			}
+		case tab:
+			e.complete(b)
+		default:
+			// Other control codes and unbound keys are ignored
+			if k >= ' ' && k != backspace {
+				b.insert([]rune{rune(k)})
+			}
+		}
+		e.refresh(b)
+	}
+}
+
+// refresh redraws the line and puts the cursor back where it belongs
+func (e *Editor) refresh(b *buffer) {
+	fmt.Fprintf(e.out, "\r%s%s\033[K", b.prompt, string(b.line))
+	if back := len(b.line) - b.pos; back > 0 {
+		fmt.Fprintf(e.out, "\033[%dD", back)
+	}
+}
+
+// search is Ctrl-R: typing finds the newest history line containing the
+// text, Ctrl-R again finds older ones. Enter runs the match, Ctrl-G or
+// Escape gives up, and any other key keeps the match for editing. done
+// reports whether Enter was pressed, with line the match.
+func (e *Editor) search(b *buffer) (line string, done bool, err error) {
+	original := string(b.line)
+	query := []rune{}
+	match := ""
+	// from is where the search starts, going back in time
+	from := e.History.Len() - 1
+	found := e.History.Len()
+
+	for {
+		fmt.Fprintf(e.out, "\r(reverse-i-search)`%s': %s\033[K", string(query), match)
+		k, err := readKey(e.in)
+		if err != nil {
+			return "", false, err
+		}
+
+		switch k {
+		case enter, ctrlJ:
+			fmt.Fprint(e.out, "\r\n")
+			if match == "" {
+				return original, true, nil
+			}
+			return match, true, nil
+		case ctrlG, keyEscape, ctrlC:
+			b.set(original)
+			return "", false, nil
+		case ctrlR:
+			from = found - 1
+		case backspace, ctrlH:
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+			}
+			from = e.History.Len() - 1
+		default:
+			if k < ' ' {
+				if match == "" {
+					match = original
+				}
+				b.set(match)
+				return "", false, nil
+			}
+			query = append(query, rune(k))
+			from = e.History.Len() - 1
+		}
+
+		if len(query) == 0 {
+			continue
+		}
+		i := from
+		for i >= 0 && !strings.Contains(e.History.At(i), string(query)) {
+			i--
+		}
+		if i >= 0 {
+			found, match = i, e.History.At(i)
+		} else {
+			// Keep the last match when nothing older contains the text
+			fmt.Fprint(e.out, "\a")
+		}
+	}
+}
+
+// complete is Tab: it completes the word before the cursor as far as the
+// candidates agree, and lists them when they don't
+func (e *Editor) complete(b *buffer) {
+	if e.Complete == nil {
+		return
+	}
+	before := string(b.line[:b.pos])
+	start, candidates := e.Complete(before)
+	if len(candidates) == 0 || start < 0 || start > len(before) {
+		fmt.Fprint(e.out, "\a")
+		return
+	}
+
+	word := before[start:]
+	prefix := commonPrefix(candidates)
+	if len(candidates) == 1 {
+		prefix += " "
+	}
+	if len(prefix) > len(word) {
+		b.deleteBack(utf8.RuneCountInString(before[:start]))
+		b.insert([]rune(prefix))
+		return
+	}
+	if len(candidates) == 1 {
+		return
+	}
+
+	// Nothing more in common; show the choices under the line
+	fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+}
+
+// commonPrefix returns the longest prefix shared by every word
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			_, size := utf8.DecodeLastRuneInString(prefix)
+			prefix = prefix[:len(prefix)-size]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,156 @@
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// newTestEditor returns an editor reading keys, with history holding
+// lines, oldest first
+func newTestEditor(keys string, lines ...string) (*Editor, *strings.Builder) {
+	out := &strings.Builder{}
+	e := &Editor{History: NewHistory(0), in: bufio.NewReader(strings.NewReader(keys)), out: out, fd: -1}
+	for _, line := range lines {
+		e.History.Add(line)
+	}
+	return e, out
+}
+
+func TestEdit(t *testing.T) {
+	const (
+		up    = "\033[A"
+		down  = "\033[B"
+		left  = "\033[D"
+		right = "\033[C"
+		home  = "\033[H"
+		del   = "\033[3~"
+	)
+	cases := []struct {
+		name     string
+		keys     string
+		expected string
+	}{
+		{name: "typing", keys: "map\r", expected: "map"},
+		{name: "unicode", keys: "catch pokémon\r", expected: "catch pokémon"},
+		{name: "backspace", keys: "mapp\x7f\r", expected: "map"},
+		{name: "insert in the middle", keys: "mp" + left + "a\r", expected: "map"},
+		{name: "home and end", keys: "ap" + home + "m\x05b\r", expected: "mapb"},
+		{name: "delete under the cursor", keys: "mapb" + left + del + "\r", expected: "map"},
+		{name: "ctrl-d deletes under the cursor", keys: "mapb" + left + "\x04\r", expected: "map"},
+		{name: "ctrl-w deletes a word", keys: "catch pikachu\x17mew\r", expected: "catch mew"},
+		{name: "ctrl-u clears before the cursor", keys: "catch pikachu\x15help\r", expected: "help"},
+		{name: "ctrl-k clears after the cursor", keys: "help me" + left + left + left + "\x0b\r", expected: "help"},
+		{name: "arrows past the ends", keys: left + "a" + right + right + "b\r", expected: "ab"},
+		{name: "unknown escapes are ignored", keys: "a\033[5~\033xb\r", expected: "ab"},
+		{name: "newest history first", keys: up + "\r", expected: "pokedex"},
+		{name: "older history", keys: up + up + "\r", expected: "explore canalave-city-area"},
+		{name: "stops at the oldest", keys: up + up + up + up + "\r", expected: "map"},
+		{name: "down returns to the draft", keys: "cat" + up + up + down + down + "ch\r", expected: "catch"},
+		{name: "history can be edited", keys: up + "\x7f\x7f\x7fmon\r", expected: "pokemon"},
+		{name: "search", keys: "\x12can\r", expected: "explore canalave-city-area"},
+		{name: "search again for older", keys: "\x12a\x12\r", expected: "map"},
+		{name: "search keeps the match for editing", keys: "\x12dex" + right + " x\r", expected: "pokedex x"},
+		{name: "search with no match keeps the line", keys: "help\x12zzz\r", expected: "help"},
+		{name: "search cancelled", keys: "help\x12map\x07\r", expected: "help"},
+		{name: "search backspace", keys: "\x12mapz\x7f\r", expected: "map"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e, _ := newTestEditor(c.keys, "map", "explore canalave-city-area", "pokedex")
+			line, err := e.edit("> ")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if line != c.expected {
+				t.Errorf("edit(%q) == %q, expected %q", c.keys, line, c.expected)
+			}
+		})
+	}
+}
+
+func TestEditErrors(t *testing.T) {
+	cases := []struct {
+		keys     string
+		expected error
+	}{
+		{keys: "\x04", expected: io.EOF},
+		{keys: "map\x03", expected: ErrInterrupted},
+		{keys: "map", expected: io.EOF},
+	}
+	for _, c := range cases {
+		e, _ := newTestEditor(c.keys)
+		if _, err := e.edit("> "); !errors.Is(err, c.expected) {
+			t.Errorf("edit(%q) error %v, expected %v", c.keys, err, c.expected)
+		}
+	}
+}
+
+func TestComplete(t *testing.T) {
+	words := []string{"catch", "cache", "explore", "pokédex", "pokémon"}
+	complete := func(line string) (int, []string) {
+		start := strings.LastIndex(line, " ") + 1
+		candidates := []string{}
+		for _, word := range words {
+			if strings.HasPrefix(word, strings.ToLower(line[start:])) {
+				candidates = append(candidates, word)
+			}
+		}
+		return start, candidates
+	}
+
+	cases := []struct {
+		name     string
+		keys     string
+		expected string
+		// listed is text expected in the output
+		listed string
+	}{
+		{name: "a single match", keys: "ex\t\r", expected: "explore "},
+		{name: "the common prefix", keys: "c\t\r", expected: "ca"},
+		{name: "lists the choices", keys: "ca\t\r", expected: "ca", listed: "catch  cache"},
+		{name: "a later word", keys: "explore ca\tt\t\r", expected: "explore catch "},
+		{name: "replaces a differently cased word", keys: "EX\t\r", expected: "explore "},
+		{name: "multibyte prefix", keys: "pok\t\r", expected: "poké"},
+		{name: "in the middle of a line", keys: "ex pikachu\033[D\033[D\033[D\033[D\033[D\033[D\033[D\033[D\t\r", expected: "explore  pikachu"},
+		{name: "no match", keys: "zz\t\r", expected: "zz", listed: "\a"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e, out := newTestEditor(c.keys)
+			e.Complete = complete
+			line, err := e.edit("> ")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if line != c.expected {
+				t.Errorf("edit(%q) == %q, expected %q", c.keys, line, c.expected)
+			}
+			if !strings.Contains(out.String(), c.listed) {
+				t.Errorf("expected %q in the output %q", c.listed, out.String())
+			}
+		})
+	}
+}
+
+func TestReadLinePlain(t *testing.T) {
+	out := &strings.Builder{}
+	e := New(strings.NewReader("map\r\nexplore area\nhelp"), out)
+	for _, expected := range []string{"map", "explore area", "help"} {
+		line, err := e.ReadLine("> ")
+		if err != nil || line != expected {
+			t.Errorf("ReadLine() == %q, %v, expected %q", line, err, expected)
+		}
+	}
+	if _, err := e.ReadLine("> "); err != io.EOF {
+		t.Errorf("expected io.EOF at the end of input, got %v", err)
+	}
+	if out.String() != "> > > > " {
+		t.Errorf("expected a prompt per line, got %q", out.String())
+	}
+	if e.History.Len() != 3 || e.History.At(2) != "help" {
+		t.Errorf("expected the lines in the history")
+	}
+}
@@ -0,0 +1,138 @@
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultHistorySize is how many lines a history keeps unless asked
+// otherwise
+const DefaultHistorySize = 1000
+
+// History is the lines entered so far, oldest first. A history with a
+// path appends every line to that file, so it survives restarts.
+type History struct {
+	entries []string
+	path    string
+	size    int
+}
+
+// NewHistory returns an empty history that isn't saved
+func NewHistory(size int) *History {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	return &History{size: size}
+}
+
+// LoadHistory reads the history file at path, which doesn't have to exist
+// yet, and keeps adding to it. Only the last size lines are kept.
+func LoadHistory(path string, size int) (*History, error) {
+	h := NewHistory(size)
+	h.path = path
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return h, fmt.Errorf("reading history: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lines := 0
+	for scanner.Scan() {
+		lines++
+		h.append(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return h, fmt.Errorf("reading history: %w", err)
+	}
+
+	// Trim the file once it holds more than the history keeps
+	if lines > h.size {
+		if err := h.rewrite(); err != nil {
+			return h, err
+		}
+	}
+	return h, nil
+}
+
+// DefaultHistoryPath returns where the REPL history is kept
+func DefaultHistoryPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating config dir: %w", err)
+	}
+	return filepath.Join(configDir, "pokedexcli", "history"), nil
+}
+
+// Add records a line. Blank lines and repeats of the last line are
+// skipped. If the line can't be saved, the error is returned once and
+// later lines are only kept in memory.
+func (h *History) Add(line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == line) {
+		return nil
+	}
+	h.append(line)
+	if h.path == "" {
+		return nil
+	}
+	if err := appendLine(h.path, line); err != nil {
+		h.path = ""
+		return fmt.Errorf("saving history: %w", err)
+	}
+	return nil
+}
+
+// appendLine adds line to the end of the file at path
+func appendLine(path, line string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(file, line); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Len returns the number of lines in the history
+func (h *History) Len() int {
+	return len(h.entries)
+}
+
+// At returns the i-th line, oldest first
+func (h *History) At(i int) string {
+	return h.entries[i]
+}
+
+func (h *History) append(line string) {
+	h.entries = append(h.entries, line)
+	if len(h.entries) > h.size {
+		h.entries = h.entries[len(h.entries)-h.size:]
+	}
+}
+
+// rewrite replaces the history file with the lines kept in memory
+func (h *History) rewrite() error {
+	tmp := h.path + ".tmp"
+	content := strings.Join(h.entries, "\n") + "\n"
+	if err := os.WriteFile(tmp, []byte(content), 0o600); err != nil {
+		return fmt.Errorf("saving history: %w", err)
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		return fmt.Errorf("saving history: %w", err)
+	}
+	return nil
+}
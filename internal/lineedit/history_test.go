@@ -0,0 +1,64 @@
+package lineedit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHistoryAdd(t *testing.T) {
+	h := NewHistory(3)
+	for _, line := range []string{"map", "", "  ", "map", "mapb", " explore area ", "pokedex"} {
+		h.Add(line)
+	}
+	expected := []string{"mapb", "explore area", "pokedex"}
+	if h.Len() != len(expected) {
+		t.Fatalf("history has %d lines, expected %d", h.Len(), len(expected))
+	}
+	for i, line := range expected {
+		if h.At(i) != line {
+			t.Errorf("At(%d) == %q, expected %q", i, h.At(i), line)
+		}
+	}
+}
+
+func TestLoadHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pokedexcli", "history")
+
+	// A missing file is an empty history, created on the first line
+	h, err := LoadHistory(path, 3)
+	if err != nil || h.Len() != 0 {
+		t.Fatalf("LoadHistory(missing) == %d lines, %v", h.Len(), err)
+	}
+	for _, line := range []string{"map", "mapb", "map", "pokedex"} {
+		if err := h.Add(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// The file has every line; loading keeps the last three and trims it
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "map\nmapb\nmap\npokedex\n" {
+		t.Fatalf("history file %q, %v", data, err)
+	}
+	h, err = LoadHistory(path, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h.Len() != 3 || h.At(0) != "mapb" || h.At(2) != "pokedex" {
+		t.Errorf("unexpected history after loading")
+	}
+	data, _ = os.ReadFile(path)
+	if string(data) != "mapb\nmap\npokedex\n" {
+		t.Errorf("expected the file to be trimmed, got %q", data)
+	}
+
+	// New lines carry on from the loaded ones
+	h.Add("pokedex")
+	h.Add("help")
+	data, _ = os.ReadFile(path)
+	if !strings.HasSuffix(string(data), "pokedex\nhelp\n") || strings.Count(string(data), "pokedex") != 1 {
+		t.Errorf("unexpected history file %q", data)
+	}
+}
@@ -0,0 +1,109 @@
+package lineedit
+
+import "bufio"
+
+// key is a keypress: a rune for characters and control codes, or one of
+// the negative constants for keys sent as escape sequences
+type key rune
+
+const (
+	keyUnknown key = -(iota + 1)
+	keyEscape
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyHome
+	keyEnd
+	keyDelete
+)
+
+// Control codes
+const (
+	ctrlA     key = 1
+	ctrlB     key = 2
+	ctrlC     key = 3
+	ctrlD     key = 4
+	ctrlE     key = 5
+	ctrlF     key = 6
+	ctrlG     key = 7
+	ctrlH     key = 8
+	tab       key = 9
+	ctrlJ     key = 10
+	ctrlK     key = 11
+	ctrlL     key = 12
+	enter     key = 13
+	ctrlN     key = 14
+	ctrlP     key = 16
+	ctrlR     key = 18
+	ctrlU     key = 21
+	ctrlW     key = 23
+	backspace key = 127
+)
+
+// readKey reads the next keypress from in
+func readKey(in *bufio.Reader) (key, error) {
+	r, _, err := in.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	if r != 27 {
+		return key(r), nil
+	}
+
+	// Terminals send escape sequences in one write, so a lone escape has
+	// nothing after it yet
+	if in.Buffered() == 0 {
+		return keyEscape, nil
+	}
+	intro, err := in.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if intro != '[' && intro != 'O' {
+		// Alt+key, which isn't bound to anything
+		return keyUnknown, nil
+	}
+
+	// Parameters, then a final byte from @ to ~
+	params := []byte{}
+	for {
+		b, err := in.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b >= 0x40 && b <= 0x7e {
+			return escapeKey(string(params), b), nil
+		}
+		params = append(params, b)
+	}
+}
+
+// escapeKey maps the parameters and final byte of an escape sequence to
+// a key
+func escapeKey(params string, final byte) key {
+	switch final {
+	case 'A':
+		return keyUp
+	case 'B':
+		return keyDown
+	case 'C':
+		return keyRight
+	case 'D':
+		return keyLeft
+	case 'H':
+		return keyHome
+	case 'F':
+		return keyEnd
+	case '~':
+		switch params {
+		case "1", "7":
+			return keyHome
+		case "4", "8":
+			return keyEnd
+		case "3":
+			return keyDelete
+		}
+	}
+	return keyUnknown
+}
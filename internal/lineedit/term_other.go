@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package lineedit
+
+import "errors"
+
+// makeRaw isn't supported here, so lines are read as the terminal sends
+// them, without editing
+func makeRaw(fd int) (func() error, error) {
+	return nil, errors.New("raw mode is not supported on this platform")
+}
+
+func isTerminal(fd int) bool {
+	return false
+}
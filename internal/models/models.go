@@ -1,16 +1,135 @@
 package models
 
-import "pokedexcli/internal/pokeapi"
+import (
+	"math/rand/v2"
+	"path"
+	"pokedexcli/internal/catchrate"
+	"pokedexcli/internal/clock"
+	"pokedexcli/internal/encounter"
+	"pokedexcli/internal/output"
+	"pokedexcli/internal/pokeapi"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Pokemon represents a caught Pokemon
 type Pokemon struct {
-	Name string
+	// ID is the species' National Pokédex number, shared by all its forms
+	ID             int       `json:"id"`
+	Name           string    `json:"name"`
+	Nickname       string    `json:"nickname,omitempty"`
+	Height         int       `json:"height"`
+	Weight         int       `json:"weight"`
+	Sprite         string    `json:"sprite,omitempty"`
+	BaseExperience int       `json:"base_experience"`
+	Types          []string  `json:"types"`
+	Stats          []Stat    `json:"stats"`
+	Abilities      []Ability `json:"abilities"`
+
+	// Details of the catch itself
+	CaughtAt    time.Time `json:"caught_at"`
+	CaughtIn    string    `json:"caught_in,omitempty"`
+	Level       int       `json:"level,omitempty"`
+	Ball        string    `json:"ball,omitempty"`
+	CatchChance float64   `json:"catch_chance"`
+}
+
+// Stat is a single base stat of a Pokemon
+type Stat struct {
+	Name     string `json:"name"`
+	BaseStat int    `json:"base_stat"`
+}
+
+// Ability is one of a Pokemon's possible abilities
+type Ability struct {
+	Name     string `json:"name"`
+	IsHidden bool   `json:"is_hidden"`
+}
+
+// NewPokemon builds a Pokedex record from an API response
+func NewPokemon(pokemonResponse pokeapi.PokemonResponse) Pokemon {
+	pokemon := Pokemon{
+		ID:             speciesID(pokemonResponse),
+		Name:           pokemonResponse.Name,
+		Height:         pokemonResponse.Height,
+		Weight:         pokemonResponse.Weight,
+		BaseExperience: pokemonResponse.BaseExperience,
+	}
+	for _, t := range pokemonResponse.Types {
+		pokemon.Types = append(pokemon.Types, t.Type.Name)
+	}
+	for _, s := range pokemonResponse.Stats {
+		pokemon.Stats = append(pokemon.Stats, Stat{Name: s.Stat.Name, BaseStat: s.BaseStat})
+	}
+	for _, a := range pokemonResponse.Abilities {
+		pokemon.Abilities = append(pokemon.Abilities, Ability{Name: a.Ability.Name, IsHidden: a.IsHidden})
+	}
+	return pokemon
+}
+
+// speciesID returns the National Pokédex number, which is the species ID.
+// The Pokémon's own ID only matches it for the default form: pikachu-alola
+// is 10xxx. Without a species URL the Pokémon's ID is all there is.
+func speciesID(pokemonResponse pokeapi.PokemonResponse) int {
+	segment := path.Base(strings.TrimSuffix(pokemonResponse.Species.URL, "/"))
+	if id, err := strconv.Atoi(segment); err == nil && id > 0 {
+		return id
+	}
+	return pokemonResponse.ID
+}
+
+// HasDetails reports whether the record holds species data. Records saved
+// before details were stored only have a name.
+func (p Pokemon) HasDetails() bool {
+	return p.ID != 0
+}
+
+// DisplayName returns the nickname if one was given, otherwise the species name
+func (p Pokemon) DisplayName() string {
+	if p.Nickname != "" {
+		return p.Nickname
+	}
+	return p.Name
+}
+
+// Store persists the REPL session between runs
+type Store interface {
+	Load(config *ReplConfig) error
+	Save(config *ReplConfig) error
 }
 
 // ReplConfig holds the state of the REPL session
 type ReplConfig struct {
 	Pokedex       map[string]Pokemon
 	PokeApiClient *pokeapi.Client
-	Next          string
-	Previous      string
+	CatchModel    catchrate.Model
+	Store         Store
+	// Rand drives every random game mechanic; Seed is what it was last
+	// seeded with, so a session can be replayed
+	Rand     *rand.Rand
+	Seed     uint64
+	Clock    clock.Clock
+	Next     string
+	Previous string
+	// PageSize is how many locations map shows, 0 for the default
+	PageSize    int
+	CurrentArea string
+	// Encounters is the encounter table of CurrentArea
+	Encounters encounter.Table
+	// Realistic only allows catching Pokémon found in CurrentArea
+	Realistic bool
+	// Version is the game version encounters are rolled for, or empty for
+	// any version
+	Version string
+	// Wild is the wild Pokémon currently being faced, if any
+	Wild *encounter.Encounter
+	// Output renders command results, the colored table if nil
+	Output output.Formatter
+}
+
+// Reseed replaces the random source with one seeded from seed
+func (c *ReplConfig) Reseed(seed uint64) {
+	c.Seed = seed
+	c.Rand = rand.New(rand.NewPCG(seed, seed))
 }
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"pokedexcli/internal/pokeapi"
+	"testing"
+)
+
+func TestNewPokemonSpeciesID(t *testing.T) {
+	cases := []struct {
+		id         int
+		speciesURL string
+		expected   int
+	}{
+		{id: 25, speciesURL: "https://pokeapi.co/api/v2/pokemon-species/25/", expected: 25},
+		// pikachu-alola is a form of species 25
+		{id: 10100, speciesURL: "https://pokeapi.co/api/v2/pokemon-species/25/", expected: 25},
+		{id: 10100, speciesURL: "https://pokeapi.co/api/v2/pokemon-species/25", expected: 25},
+		{id: 25, speciesURL: "", expected: 25},
+	}
+	for _, c := range cases {
+		response := pokeapi.PokemonResponse{ID: c.id}
+		response.Species.URL = c.speciesURL
+		if actual := NewPokemon(response).ID; actual != c.expected {
+			t.Errorf("NewPokemon(id %d, species %q).ID == %d, expected %d", c.id, c.speciesURL, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Package output renders command results as colored tables, JSON or YAML.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Formatter renders a command result to w
+type Formatter interface {
+	Format(w io.Writer, result any) error
+}
+
+// Tabular is a result that knows how to print itself for people
+type Tabular interface {
+	WriteTable(w io.Writer) error
+}
+
+// formatters maps --output names to formatters
+var formatters = map[string]Formatter{
+	"table": Table{},
+	"json":  JSON{},
+	"yaml":  YAML{},
+}
+
+// Names lists the formats New accepts
+func Names() []string {
+	return []string{"table", "json", "yaml"}
+}
+
+// New returns the formatter called name
+func New(name string) (Formatter, error) {
+	formatter, ok := formatters[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format %q, try one of: %s", name, strings.Join(Names(), ", "))
+	}
+	return formatter, nil
+}
+
+// IsTable reports whether f prints for people rather than programs. A nil
+// formatter is the default table.
+func IsTable(f Formatter) bool {
+	_, ok := f.(Table)
+	return f == nil || ok
+}
+
+// Table prints results the way the REPL always has. Results that can't
+// print themselves fall back to YAML, which still reads well.
+type Table struct{}
+
+func (Table) Format(w io.Writer, result any) error {
+	if tabular, ok := result.(Tabular); ok {
+		return tabular.WriteTable(w)
+	}
+	return YAML{}.Format(w, result)
+}
+
+// JSON prints each result as an indented JSON document
+type JSON struct{}
+
+func (JSON) Format(w io.Writer, result any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
@@ -0,0 +1,159 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+type stat struct {
+	Name     string `json:"name"`
+	BaseStat int    `json:"base_stat"`
+}
+
+type pokemon struct {
+	Name     string            `json:"name"`
+	Nickname string            `json:"nickname,omitempty"`
+	Types    []string          `json:"types"`
+	Stats    []stat            `json:"stats"`
+	Chance   float64           `json:"chance"`
+	Caught   bool              `json:"caught"`
+	CaughtAt time.Time         `json:"caught_at"`
+	Extra    map[string]string `json:"extra"`
+	Moves    []string          `json:"moves"`
+	Grid     [][]int           `json:"grid"`
+}
+
+// tabular prints itself as a table
+type tabular struct{}
+
+func (tabular) WriteTable(w io.Writer) error {
+	_, err := io.WriteString(w, "a table\n")
+	return err
+}
+
+func TestYAML(t *testing.T) {
+	result := pokemon{
+		Name:     "mr-mime",
+		Types:    []string{"psychic", "fairy"},
+		Stats:    []stat{{Name: "hp", BaseStat: 40}, {Name: "speed", BaseStat: 90}},
+		Chance:   0.25,
+		Caught:   true,
+		CaughtAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Extra:    map[string]string{"zip": "10", "note": "has: colon", "empty": "", "yes": "no"},
+		Moves:    []string{},
+		Grid:     [][]int{{1, 2}, {}},
+	}
+	expected := `---
+name: mr-mime
+types:
+  - psychic
+  - fairy
+stats:
+  - name: hp
+    base_stat: 40
+  - name: speed
+    base_stat: 90
+chance: 0.25
+caught: true
+caught_at: "2024-05-01T12:00:00Z"
+extra:
+  empty: ""
+  note: "has: colon"
+  "yes": "no"
+  zip: "10"
+moves: []
+grid:
+  -
+    - 1
+    - 2
+  - []
+`
+	var b bytes.Buffer
+	if err := (YAML{}).Format(&b, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.String() != expected {
+		t.Errorf("YAML output:\n%s\nexpected:\n%s", b.String(), expected)
+	}
+}
+
+func TestYAMLScalars(t *testing.T) {
+	cases := []struct {
+		value    any
+		expected string
+	}{
+		{value: "pikachu", expected: "---\npikachu\n"},
+		{value: 42, expected: "---\n42\n"},
+		{value: nil, expected: "---\nnull\n"},
+		{value: map[string]int{}, expected: "---\n{}\n"},
+		{value: []string{"a"}, expected: "---\n- a\n"},
+		{value: "line\nbreak", expected: "---\n\"line\\nbreak\"\n"},
+		{value: "- dash", expected: "---\n\"- dash\"\n"},
+		{value: "<tag> & co", expected: "---\n<tag> & co\n"},
+		{value: "&anchor", expected: "---\n\"&anchor\"\n"},
+		{value: "2024-05-01T12:00:00Z", expected: "---\n\"2024-05-01T12:00:00Z\"\n"},
+		{value: "2024-05-01", expected: "---\n\"2024-05-01\"\n"},
+		{value: "0x1F", expected: "---\n\"0x1F\"\n"},
+		{value: "0o17", expected: "---\n\"0o17\"\n"},
+		{value: "1_000", expected: "---\n\"1_000\"\n"},
+		{value: ".inf", expected: "---\n\".inf\"\n"},
+		{value: "+12", expected: "---\n\"+12\"\n"},
+		{value: "NaN", expected: "---\n\"NaN\"\n"},
+		{value: "mr-mime 2", expected: "---\nmr-mime 2\n"},
+	}
+	for _, c := range cases {
+		var b bytes.Buffer
+		if err := (YAML{}).Format(&b, c.value); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if b.String() != c.expected {
+			t.Errorf("YAML(%#v) == %q, expected %q", c.value, b.String(), c.expected)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var b bytes.Buffer
+	if err := (JSON{}).Format(&b, stat{Name: "<hp>", BaseStat: 40}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "{\n  \"name\": \"<hp>\",\n  \"base_stat\": 40\n}\n"
+	if b.String() != expected {
+		t.Errorf("JSON output %q, expected %q", b.String(), expected)
+	}
+}
+
+func TestTable(t *testing.T) {
+	var b bytes.Buffer
+	if err := (Table{}).Format(&b, tabular{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.String() != "a table\n" {
+		t.Errorf("expected the result's own table, got %q", b.String())
+	}
+
+	// Anything else falls back to YAML
+	b.Reset()
+	if err := (Table{}).Format(&b, stat{Name: "hp", BaseStat: 40}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.String() != "---\nname: hp\nbase_stat: 40\n" {
+		t.Errorf("unexpected fallback %q", b.String())
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, name := range append(Names(), "JSON") {
+		if _, err := New(name); err != nil {
+			t.Errorf("New(%q): unexpected error: %v", name, err)
+		}
+	}
+	if _, err := New("xml"); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+	if !IsTable(nil) || !IsTable(Table{}) || IsTable(JSON{}) {
+		t.Errorf("IsTable reported the wrong formats")
+	}
+}
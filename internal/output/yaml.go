@@ -0,0 +1,207 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// YAML prints each result as a YAML document. Results are described by
+// their json tags, so field names, omitempty and custom marshalers behave
+// exactly as they do for JSON.
+type YAML struct{}
+
+func (YAML) Format(w io.Writer, result any) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	root, err := readNode(decoder)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	switch {
+	case root.kind == mapping && len(root.keys) > 0:
+		writeMapping(&b, root, 0, false)
+	case root.kind == sequence && len(root.items) > 0:
+		writeSequence(&b, root, 0)
+	default:
+		b.WriteString(root.inline() + "\n")
+	}
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+type nodeKind int
+
+const (
+	scalar nodeKind = iota
+	mapping
+	sequence
+)
+
+// node is a decoded JSON value that keeps the order of object keys
+type node struct {
+	kind nodeKind
+	// value is the rendered scalar
+	value string
+	keys  []string
+	items []*node
+}
+
+// inline renders scalars and empty collections, which fit on one line
+func (n *node) inline() string {
+	switch {
+	case n.kind == mapping && len(n.keys) == 0:
+		return "{}"
+	case n.kind == sequence && len(n.items) == 0:
+		return "[]"
+	default:
+		return n.value
+	}
+}
+
+func (n *node) isInline() bool {
+	return n.kind == scalar || (n.kind == mapping && len(n.keys) == 0) || (n.kind == sequence && len(n.items) == 0)
+}
+
+// readNode reads the next JSON value from decoder
+func readNode(decoder *json.Decoder) (*node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		n := &node{kind: mapping}
+		if t == '[' {
+			n.kind = sequence
+		}
+		for decoder.More() {
+			if n.kind == mapping {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				n.keys = append(n.keys, key.(string))
+			}
+			item, err := readNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, item)
+		}
+		// The closing delimiter
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case string:
+		return &node{value: quote(t)}, nil
+	case json.Number:
+		return &node{value: t.String()}, nil
+	case bool:
+		return &node{value: strconv.FormatBool(t)}, nil
+	case nil:
+		return &node{value: "null"}, nil
+	default:
+		return nil, fmt.Errorf("unexpected JSON token %v", token)
+	}
+}
+
+// writeMapping writes the keys of n at indent. With inline set the first
+// key continues the current line, after a sequence dash.
+func writeMapping(b *strings.Builder, n *node, indent int, inline bool) {
+	for i, key := range n.keys {
+		if i > 0 || !inline {
+			b.WriteString(strings.Repeat(" ", indent))
+		}
+		b.WriteString(quote(key) + ":")
+		writeValue(b, n.items[i], indent)
+	}
+}
+
+// writeSequence writes the items of n as a list at indent
+func writeSequence(b *strings.Builder, n *node, indent int) {
+	for _, item := range n.items {
+		b.WriteString(strings.Repeat(" ", indent) + "-")
+		switch {
+		case item.isInline():
+			b.WriteString(" " + item.inline() + "\n")
+		case item.kind == mapping:
+			b.WriteString(" ")
+			writeMapping(b, item, indent+2, true)
+		default:
+			b.WriteString("\n")
+			writeSequence(b, item, indent+2)
+		}
+	}
+}
+
+// writeValue writes the value of a key whose line starts at indent
+func writeValue(b *strings.Builder, n *node, indent int) {
+	switch {
+	case n.isInline():
+		b.WriteString(" " + n.inline() + "\n")
+	case n.kind == mapping:
+		b.WriteString("\n")
+		writeMapping(b, n, indent+2, false)
+	default:
+		b.WriteString("\n")
+		writeSequence(b, n, indent+2)
+	}
+}
+
+// quote returns s as a plain YAML scalar when that reads back as the same
+// string, in YAML 1.1 parsers too, and double quoted otherwise
+func quote(s string) string {
+	if needsQuotes(s) {
+		var b bytes.Buffer
+		encoder := json.NewEncoder(&b)
+		encoder.SetEscapeHTML(false)
+		encoder.Encode(s)
+		// JSON strings are valid double quoted YAML scalars
+		return strings.TrimSuffix(b.String(), "\n")
+	}
+	return s
+}
+
+func needsQuotes(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return true
+	}
+	// Words YAML would read as something other than a string
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "y", "n", "null", "~":
+		return true
+	}
+	// Numbers, timestamps and YAML 1.1 forms like 0x1F, 1_000, 1:30 and
+	// .inf all start with a digit, a dot or a sign; "-" is checked below
+	if strings.ContainsAny(s[:1], "0123456789.+") {
+		return true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		// inf and nan
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return true
+	}
+	for _, r := range s {
+		if r < ' ' || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+// bulk.go
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// defaultConcurrency is used when BulkOptions.Concurrency is unset. The
+// rate limiter decides the overall request rate; this only bounds how
+// many requests are in flight.
+const defaultConcurrency = 4
+
+// BulkOptions configures FetchAll and FetchStream
+type BulkOptions struct {
+	// Concurrency is the number of workers fetching at once
+	Concurrency int
+	// Progress, if set, is called after each key finishes with the number
+	// of finished keys and the total. Calls never overlap.
+	Progress func(done, total int)
+}
+
+// Result is the outcome of fetching a single key
+type Result[K, V any] struct {
+	// Index is the position of Key in the keys passed in
+	Index int
+	Key   K
+	Value V
+	Err   error
+}
+
+// BulkError reports the keys that failed in a bulk fetch
+type BulkError struct {
+	Total    int
+	Failures []error
+}
+
+func (e *BulkError) Error() string {
+	return fmt.Sprintf("%d of %d requests failed, first error: %v", len(e.Failures), e.Total, e.Failures[0])
+}
+
+func (e *BulkError) Unwrap() []error {
+	return e.Failures
+}
+
+// FetchStream calls fetch for every key using a bounded pool of workers
+// and sends each result as soon as it is ready, in completion order. The
+// channel is closed once every key has been fetched or ctx is cancelled;
+// keys not started by then get no result. Callers must drain the channel.
+func FetchStream[K, V any](ctx context.Context, keys []K, fetch func(context.Context, K) (V, error), opts BulkOptions) <-chan Result[K, V] {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	jobs := make(chan int)
+	results := make(chan Result[K, V])
+	out := make(chan Result[K, V])
+
+	// Feed key indexes to the workers until we run out or are cancelled
+	go func() {
+		defer close(jobs)
+		for i := range keys {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				value, err := fetch(ctx, keys[i])
+				results <- Result[K, V]{Index: i, Key: keys[i], Value: value, Err: err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Report progress from a single goroutine so callbacks don't overlap
+	go func() {
+		defer close(out)
+		done := 0
+		for result := range results {
+			done++
+			if opts.Progress != nil {
+				opts.Progress(done, len(keys))
+			}
+			out <- result
+		}
+	}()
+	return out
+}
+
+// FetchAll is like FetchStream but waits for every key and returns the
+// results in the same order as keys. If some keys failed, the error is a
+// *BulkError and the results still hold every successful value. If ctx is
+// cancelled, the results fetched so far are returned with ctx's error.
+func FetchAll[K, V any](ctx context.Context, keys []K, fetch func(context.Context, K) (V, error), opts BulkOptions) ([]Result[K, V], error) {
+	results := make([]Result[K, V], len(keys))
+	fetched := make([]bool, len(keys))
+	for result := range FetchStream(ctx, keys, fetch, opts) {
+		results[result.Index] = result
+		fetched[result.Index] = true
+	}
+
+	if err := ctx.Err(); err != nil {
+		// Mark the keys we never got to so callers can tell them apart
+		for i := range results {
+			if !fetched[i] {
+				results[i] = Result[K, V]{Index: i, Key: keys[i], Err: err}
+			}
+		}
+		return results, err
+	}
+
+	failures := []error{}
+	for _, result := range results {
+		if result.Err != nil {
+			failures = append(failures, fmt.Errorf("%v: %w", result.Key, result.Err))
+		}
+	}
+	if len(failures) > 0 {
+		return results, &BulkError{Total: len(keys), Failures: failures}
+	}
+	return results, nil
+}
@@ -0,0 +1,167 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchAllKeepsOrder(t *testing.T) {
+	keys := []int{5, 4, 3, 2, 1}
+	results, err := FetchAll(context.Background(), keys, func(ctx context.Context, key int) (string, error) {
+		// Finish out of order
+		time.Sleep(time.Duration(key) * time.Millisecond)
+		return strconv.Itoa(key * 10), nil
+	}, BulkOptions{Concurrency: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, result := range results {
+		if result.Index != i || result.Key != keys[i] || result.Value != strconv.Itoa(keys[i]*10) {
+			t.Errorf("result %d out of order: %+v", i, result)
+		}
+	}
+}
+
+func TestFetchAllBoundsConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	keys := make([]int, 20)
+	_, err := FetchAll(context.Background(), keys, func(ctx context.Context, key int) (int, error) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+		running.Add(-1)
+		return key, nil
+	}, BulkOptions{Concurrency: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if peak.Load() > 3 {
+		t.Errorf("expected at most 3 concurrent fetches, saw %d", peak.Load())
+	}
+}
+
+func TestFetchAllReportsPartialFailure(t *testing.T) {
+	errOdd := errors.New("odd key")
+	keys := []int{1, 2, 3, 4}
+	results, err := FetchAll(context.Background(), keys, func(ctx context.Context, key int) (int, error) {
+		if key%2 == 1 {
+			return 0, errOdd
+		}
+		return key, nil
+	}, BulkOptions{})
+
+	var bulkErr *BulkError
+	if !errors.As(err, &bulkErr) {
+		t.Fatalf("expected BulkError, got %v", err)
+	}
+	if len(bulkErr.Failures) != 2 || bulkErr.Total != 4 {
+		t.Errorf("expected 2 of 4 failures, got %d of %d", len(bulkErr.Failures), bulkErr.Total)
+	}
+	if !errors.Is(err, errOdd) {
+		t.Errorf("expected BulkError to wrap the individual errors")
+	}
+	if results[1].Value != 2 || results[3].Value != 4 {
+		t.Errorf("expected successful results to be kept: %+v", results)
+	}
+}
+
+func TestFetchStreamProgress(t *testing.T) {
+	keys := []string{"a", "b", "c"}
+	var mutex sync.Mutex
+	calls := []string{}
+	stream := FetchStream(context.Background(), keys, func(ctx context.Context, key string) (string, error) {
+		return strings.ToUpper(key), nil
+	}, BulkOptions{Progress: func(done, total int) {
+		mutex.Lock()
+		calls = append(calls, fmt.Sprintf("%d/%d", done, total))
+		mutex.Unlock()
+	}})
+
+	seen := map[string]string{}
+	for result := range stream {
+		seen[result.Key] = result.Value
+	}
+	if len(seen) != 3 || seen["b"] != "B" {
+		t.Errorf("unexpected results: %v", seen)
+	}
+	if strings.Join(calls, ",") != "1/3,2/3,3/3" {
+		t.Errorf("unexpected progress calls: %v", calls)
+	}
+}
+
+func TestFetchAllCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	keys := make([]int, 100)
+	var started atomic.Int32
+	results, err := FetchAll(ctx, keys, func(ctx context.Context, key int) (int, error) {
+		if started.Add(1) == 2 {
+			cancel()
+		}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}, BulkOptions{Concurrency: 2})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(results) != len(keys) {
+		t.Fatalf("expected a result per key, got %d", len(results))
+	}
+	if started.Load() >= int32(len(keys)) {
+		t.Errorf("expected cancellation to stop new fetches")
+	}
+}
+
+func TestGetBaseExperienceStats(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/pokemon/"))
+		switch id {
+		case 3:
+			// Newer Pokemon have a null base experience
+			w.Write([]byte(`{"id": 3, "base_experience": null}`))
+		case 4:
+			http.NotFound(w, r)
+		default:
+			fmt.Fprintf(w, `{"id": %d, "base_experience": %d}`, id, id*100)
+		}
+	}), WithRateLimit(0, 0), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+	stats, err := client.GetBaseExperienceStats(context.Background(), 5, BulkOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Values are 100, 200 and 500
+	if stats.Count != 3 || stats.Min != 100 || stats.Max != 500 {
+		t.Errorf("unexpected count/min/max: %+v", stats)
+	}
+	if stats.Mean < 266.6 || stats.Mean > 266.7 {
+		t.Errorf("expected mean of 266.67, got %v", stats.Mean)
+	}
+	if stats.P50 != 200 || stats.P25 != 150 {
+		t.Errorf("unexpected percentiles: %+v", stats)
+	}
+	if len(stats.Missing) != 1 || stats.Missing[0] != 3 {
+		t.Errorf("expected 3 to be missing, got %v", stats.Missing)
+	}
+	if len(stats.Failed) != 1 || stats.Failed[0] != 4 {
+		t.Errorf("expected 4 to fail, got %v", stats.Failed)
+	}
+
+	for _, count := range []int{0, -1} {
+		if _, err := client.GetBaseExperienceStats(context.Background(), count, BulkOptions{}); err == nil {
+			t.Errorf("GetBaseExperienceStats(%d): expected an error", count)
+		}
+	}
+}
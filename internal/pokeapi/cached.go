@@ -0,0 +1,114 @@
+// cached.go
+package pokeapi
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// nameIndex remembers the names of the resources in the cache, so
+// completion can list them without reading it
+type nameIndex struct {
+	mutex sync.Mutex
+	names map[string]map[string]bool // resource -> names
+}
+
+func (n *nameIndex) add(resource, name string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.names == nil {
+		n.names = make(map[string]map[string]bool)
+	}
+	if n.names[resource] == nil {
+		n.names[resource] = make(map[string]bool)
+	}
+	n.names[resource][name] = true
+}
+
+// parseKey splits a cache key into its resource and, for a single
+// resource like /pokemon/pikachu, its name. List pages have no name.
+func (c *Client) parseKey(key string) (resource, name string, ok bool) {
+	rest, ok := strings.CutPrefix(key, c.baseURL+"/")
+	if !ok {
+		return "", "", false
+	}
+	path, _, _ := strings.Cut(rest, "?")
+	resource, name, single := strings.Cut(path, "/")
+	if !single {
+		return resource, "", true
+	}
+	name, err := url.PathUnescape(name)
+	if err != nil || name == "" || strings.Contains(name, "/") {
+		return "", "", false
+	}
+	return resource, name, true
+}
+
+// seedNames indexes the responses already in the cache, e.g. those a disk
+// cache kept from earlier runs. List pages are read with Peek so seeding
+// doesn't show up in the cache stats.
+func (c *Client) seedNames() {
+	for _, key := range c.cache.Keys() {
+		resource, name, ok := c.parseKey(key)
+		if !ok {
+			continue
+		}
+		if name != "" {
+			c.names.add(resource, name)
+			continue
+		}
+		if body, ok := c.cache.Peek(key); ok {
+			c.indexPage(resource, body)
+		}
+	}
+}
+
+// index records the names a response fetched from key reveals: the name
+// of a single resource, or everything on a list page
+func (c *Client) index(key string, body []byte) {
+	resource, name, ok := c.parseKey(key)
+	if !ok {
+		return
+	}
+	if name != "" {
+		c.names.add(resource, name)
+		return
+	}
+	c.indexPage(resource, body)
+}
+
+// indexPage records the names on a list page of resource
+func (c *Client) indexPage(resource string, body []byte) {
+	page := struct {
+		Results []struct {
+			Name string `json:"name"`
+		} `json:"results"`
+	}{}
+	if json.Unmarshal(body, &page) != nil {
+		return
+	}
+	for _, result := range page.Results {
+		if result.Name != "" {
+			c.names.add(resource, result.Name)
+		}
+	}
+}
+
+// KnownNames lists the names of a resource, e.g. "pokemon" or
+// "location-area", that are known without a request: those cached by
+// name, and those on cached list pages, including pages a disk cache kept
+// from earlier runs. It is meant for tab completion, so it doesn't read
+// the cache or count as a hit.
+func (c *Client) KnownNames(resource string) []string {
+	c.names.mutex.Lock()
+	defer c.names.mutex.Unlock()
+	names := make([]string, 0, len(c.names.names[resource]))
+	for name := range c.names.names[resource] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -2,71 +2,145 @@
 package pokeapi
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"pokedexcli/internal/pokecache"
+	"strings"
 	"time"
 )
 
+const (
+	// DefaultBaseURL is the public PokeAPI
+	DefaultBaseURL = "https://pokeapi.co/api/v2"
+
+	defaultUserAgent = "pokedexcli"
+
+	// PokeAPI asks clients to limit how often they call it; these defaults
+	// keep bulk fetches well within fair use
+	defaultRateLimit = 10
+	defaultBurst     = 20
+
+	// defaultCacheBytes bounds the default in-memory cache. A single Pokemon
+	// response is a few hundred KB, so this holds a couple hundred of them.
+	defaultCacheBytes = 64 << 20
+)
+
 type Client struct {
-	cache  *pokecache.Cache
-	client *http.Client
+	cache     pokecache.Cache
+	client    *http.Client
+	baseURL   string
+	userAgent string
+	retry     RetryPolicy
+	limiter   *rateLimiter
+	names     nameIndex
 }
 
-func NewClient() *Client {
-	return &Client{
-		cache: pokecache.NewCache(10 * time.Minute),
+func NewClient(opts ...Option) *Client {
+	c := &Client{
 		client: &http.Client{
 			Timeout: 5 * time.Second,
 		},
+		baseURL:   DefaultBaseURL,
+		userAgent: defaultUserAgent,
+		retry:     DefaultRetryPolicy,
+		limiter:   newRateLimiter(defaultRateLimit, defaultBurst),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	// Only start the default cache's reaper when no cache was supplied
+	if c.cache == nil {
+		c.cache = pokecache.NewCache(10*time.Minute, pokecache.WithMaxBytes(defaultCacheBytes))
+	}
+	c.seedNames()
+	return c
+}
+
+// Cache returns the response cache used by the client
+func (c *Client) Cache() pokecache.Cache {
+	return c.cache
 }
 
-// fetchJSON is a private helper that handles the common HTTP + cache pattern
-func (c *Client) fetchJSON(url string, target interface{}) error {
+// endpoint builds the URL of a resource below the base URL, e.g.
+// endpoint(nil, "pokemon", "pikachu"). Segments are path escaped.
+func (c *Client) endpoint(query url.Values, segments ...string) string {
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		escaped[i] = url.PathEscape(segment)
+	}
+	endpoint := c.baseURL + "/" + strings.Join(escaped, "/")
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	return endpoint
+}
+
+// fetchJSON is a private helper that handles the common HTTP + cache pattern.
+// The request is abandoned as soon as ctx is cancelled.
+func (c *Client) fetchJSON(ctx context.Context, url string, target interface{}) error {
 	// Check if cached val exists
 	cachedVal, cachedValExists := c.cache.Get(url)
 	if cachedValExists {
 		// log.Printf("cached value exists, key: %s, val: %s", url, cachedVal)
-		err := json.Unmarshal(cachedVal, &target)
+		err := json.Unmarshal(cachedVal, target)
 		if err != nil {
-			return fmt.Errorf("unexpected unmarshal error: %w", err)
+			return decodeError(url, err)
 		}
+		c.index(url, cachedVal)
 		return nil
 	}
 	// Cached val does not exist, must make request
-	// Get locations
-	req, err := http.NewRequest("GET", url, nil)
+	body, err := c.getWithRetry(ctx, url)
 	if err != nil {
 		return err
 	}
 
+	// Unmarshal the body into the target
+	err = json.Unmarshal(body, target)
+	if err != nil {
+		return decodeError(url, err)
+	}
+	// Add value to cache for later
+	c.cache.Add(url, body)
+	c.index(url, body)
+	return nil
+}
+
+// get performs a single GET request and returns the response body.
+// Every request, including retries, waits for the rate limiter first.
+func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+
 	// Send request
 	res, err := c.client.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer res.Body.Close()
 
 	// Check status code
 	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("request had unexpected status code: %d", res.StatusCode)
+		return nil, &StatusError{
+			URL:        url,
+			StatusCode: res.StatusCode,
+			RetryAfter: parseRetryAfter(res.Header.Get("Retry-After"), time.Now()),
+		}
 	}
 
 	// Read response body, convert http response body to byte slice
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return err
-	}
-
-	// Unmarshal the body into the target
-	err = json.Unmarshal(body, target)
-	if err != nil {
-		return err
-	}
-	// Add value to cache for later
-	c.cache.Add(url, body)
-	return nil
+	return io.ReadAll(res.Body)
 }
@@ -0,0 +1,461 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"pokedexcli/internal/pokecache"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client whose requests are served by handler
+func newTestClient(t *testing.T, handler http.Handler, opts ...Option) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	opts = append([]Option{WithBaseURL(server.URL)}, opts...)
+	return NewClient(opts...)
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestGetPokemonInformation(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pokemon/pikachu" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"id": 25, "name": "pikachu", "base_experience": 112}`))
+	}))
+
+	pokemon, err := client.GetPokemonInformation(context.Background(), "pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pokemon.ID != 25 || pokemon.BaseExperience != 112 {
+		t.Errorf("unexpected pokemon: %+v", pokemon)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	cases := []struct {
+		name       string
+		status     int
+		body       string
+		expected   error
+		statusCode int
+	}{
+		{name: "not found", status: http.StatusNotFound, body: "Not Found", expected: ErrNotFound, statusCode: 404},
+		{name: "rate limited", status: http.StatusTooManyRequests, expected: ErrRateLimited, statusCode: 429},
+		{name: "server error", status: http.StatusBadGateway, expected: ErrUpstream, statusCode: 502},
+		{name: "bad json", status: http.StatusOK, body: "<html>", expected: ErrDecode},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(c.status)
+				w.Write([]byte(c.body))
+			}), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+			_, err := client.GetPokemonInformation(context.Background(), "pikachuu")
+			if !errors.Is(err, c.expected) {
+				t.Fatalf("expected %v, got %v", c.expected, err)
+			}
+
+			var statusErr *StatusError
+			if c.statusCode == 0 {
+				if errors.As(err, &statusErr) {
+					t.Errorf("expected no status error, got %v", statusErr)
+				}
+				return
+			}
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != c.statusCode {
+				t.Errorf("expected status code %d, got %v", c.statusCode, err)
+			}
+		})
+	}
+}
+
+func TestErrorsAreNotCached(t *testing.T) {
+	calls := 0
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"id": 1, "name": "canalave-city-area"}`))
+	}), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+	ctx := context.Background()
+	if _, err := client.GetLocationAreasDetail(ctx, "canalave-city-area"); !errors.Is(err, ErrUpstream) {
+		t.Fatalf("expected upstream error, got %v", err)
+	}
+	area, err := client.GetLocationAreasDetail(ctx, "canalave-city-area")
+	if err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if area.ID != 1 {
+		t.Errorf("unexpected area: %+v", area)
+	}
+}
+
+func TestCancelledContext(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.GetPokemonInformation(ctx, "pikachu"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	var userAgent string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"count": 1, "results": [{"name": "canalave-city-area"}]}`))
+	}), WithUserAgent("pokedexcli-test"))
+
+	list, err := client.ListLocationAreas(context.Background(), ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list.Results) != 1 || list.Results[0].Name != "canalave-city-area" {
+		t.Errorf("unexpected list: %+v", list)
+	}
+	if userAgent != "pokedexcli-test" {
+		t.Errorf("expected custom user agent, got %q", userAgent)
+	}
+}
+
+func TestWithTransport(t *testing.T) {
+	var requested string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requested = req.URL.String()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"id": 1, "name": "bulbasaur"}`)),
+			Header:     http.Header{},
+			Request:    req,
+		}, nil
+	})
+	client := NewClient(WithBaseURL("https://mirror.example.com/api/v2/"), WithTransport(transport))
+
+	pokemon, err := client.GetPokemonInformation(context.Background(), "bulbasaur")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pokemon.Name != "bulbasaur" {
+		t.Errorf("unexpected pokemon: %+v", pokemon)
+	}
+	if requested != "https://mirror.example.com/api/v2/pokemon/bulbasaur" {
+		t.Errorf("unexpected request URL %q", requested)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	}), WithTimeout(10*time.Millisecond), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+	if _, err := client.GetPokemonInformation(context.Background(), "pikachu"); err == nil {
+		t.Errorf("expected timeout error")
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	client := NewClient(WithBaseURL("http://localhost:8000/api/v2"))
+	cases := []struct {
+		actual   string
+		expected string
+	}{
+		{
+			actual:   client.endpoint(nil, "pokemon", "mr-mime"),
+			expected: "http://localhost:8000/api/v2/pokemon/mr-mime",
+		},
+		{
+			actual:   client.endpoint(nil, "pokemon", "a/b"),
+			expected: "http://localhost:8000/api/v2/pokemon/a%2Fb",
+		},
+	}
+	for _, c := range cases {
+		if c.actual != c.expected {
+			t.Errorf("endpoint == %q, expected %q", c.actual, c.expected)
+		}
+	}
+}
+
+func TestGetVersion(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/version/yellow" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"id": 3, "name": "yellow", "version_group": {"name": "yellow"}}`))
+	}), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+	version, err := client.GetVersion(context.Background(), "yellow")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if version.ID != 3 || version.VersionGroup.Name != "yellow" {
+		t.Errorf("unexpected version: %+v", version)
+	}
+	if _, err := client.GetVersion(context.Background(), "purple"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSprite(t *testing.T) {
+	var pokemon PokemonResponse
+	pokemon.Sprites.FrontDefault = "default.png"
+	pokemon.Sprites.Versions.GenerationI.Yellow.FrontDefault = "yellow.png"
+	pokemon.Sprites.Versions.GenerationIv.HeartgoldSoulsilver.FrontDefault = "hgss.png"
+
+	cases := []struct {
+		version  string
+		expected string
+	}{
+		{version: "yellow", expected: "yellow.png"},
+		{version: "soulsilver", expected: "hgss.png"},
+		{version: "red", expected: "default.png"},
+		{version: "scarlet", expected: "default.png"},
+		{version: "", expected: "default.png"},
+	}
+	for _, c := range cases {
+		if sprite := pokemon.Sprite(c.version); sprite != c.expected {
+			t.Errorf("Sprite(%q) == %q, expected %q", c.version, sprite, c.expected)
+		}
+	}
+}
+
+func TestRegionsAndLocations(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/region":
+			if r.URL.Query().Get("limit") == "" {
+				t.Errorf("expected regions to be requested in one page")
+			}
+			w.Write([]byte(`{"count": 2, "results": [{"name": "kanto"}, {"name": "johto"}]}`))
+		case "/region/kanto":
+			w.Write([]byte(`{"id": 1, "name": "kanto", "main_generation": {"name": "generation-i"}, "locations": [{"name": "viridian-forest"}]}`))
+		case "/location/viridian-forest":
+			w.Write([]byte(`{"id": 321, "name": "viridian-forest", "region": {"name": "kanto"}, "areas": [{"name": "viridian-forest-area"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	ctx := context.Background()
+
+	regions, err := client.GetRegionsList(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if regions.Count != 2 || regions.Results[1].Name != "johto" {
+		t.Errorf("unexpected regions: %+v", regions)
+	}
+
+	region, err := client.GetRegion(ctx, "kanto")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if region.MainGeneration.Name != "generation-i" || len(region.Locations) != 1 || region.Locations[0].Name != "viridian-forest" {
+		t.Errorf("unexpected region: %+v", region)
+	}
+
+	location, err := client.GetLocation(ctx, "viridian-forest")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if location.Region.Name != "kanto" || len(location.Areas) != 1 || location.Areas[0].Name != "viridian-forest-area" {
+		t.Errorf("unexpected location: %+v", location)
+	}
+
+	if _, err := client.GetLocation(ctx, "atlantis"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestListLocationAreas(t *testing.T) {
+	var query url.Values
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/location-area" {
+			http.NotFound(w, r)
+			return
+		}
+		query = r.URL.Query()
+		w.Write([]byte(`{"count": 1089, "next": "https://pokeapi.co/api/v2/location-area?offset=90&limit=30", "results": [{"name": "route-1-area"}]}`))
+	}))
+
+	cases := []struct {
+		opts   ListOptions
+		offset string
+		limit  string
+	}{
+		{opts: ListOptions{}, offset: "0", limit: "20"},
+		{opts: ListOptions{Offset: 60, Limit: 30}, offset: "60", limit: "30"},
+	}
+	for _, c := range cases {
+		list, err := client.ListLocationAreas(context.Background(), c.opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if query.Get("offset") != c.offset || query.Get("limit") != c.limit {
+			t.Errorf("ListLocationAreas(%+v) requested %v", c.opts, query)
+		}
+		if list.Count != 1089 || len(list.Results) != 1 {
+			t.Errorf("unexpected list: %+v", list)
+		}
+	}
+
+	if _, err := client.ListLocationAreas(context.Background(), ListOptions{Offset: -1}); err == nil {
+		t.Errorf("expected an error for a negative offset")
+	}
+}
+
+func TestParseListOptions(t *testing.T) {
+	cases := []struct {
+		url      string
+		expected ListOptions
+		page     int
+		pages    int
+		err      bool
+	}{
+		{url: "https://pokeapi.co/api/v2/location-area?offset=90&limit=30", expected: ListOptions{Offset: 90, Limit: 30}, page: 4, pages: 37},
+		{url: "https://pokeapi.co/api/v2/location-area", expected: ListOptions{}, page: 1, pages: 55},
+		{url: "https://pokeapi.co/api/v2/location-area?offset=-20", err: true},
+		{url: "https://pokeapi.co/api/v2/location-area?limit=lots", err: true},
+		{url: "://", err: true},
+	}
+	for _, c := range cases {
+		opts, err := ParseListOptions(c.url)
+		if (err != nil) != c.err {
+			t.Errorf("ParseListOptions(%q) error == %v, expected error %v", c.url, err, c.err)
+			continue
+		}
+		if c.err {
+			continue
+		}
+		if opts != c.expected {
+			t.Errorf("ParseListOptions(%q) == %+v, expected %+v", c.url, opts, c.expected)
+		}
+		if opts.Page() != c.page || opts.Pages(1089) != c.pages {
+			t.Errorf("%+v is page %d of %d, expected %d of %d", opts, opts.Page(), opts.Pages(1089), c.page, c.pages)
+		}
+	}
+}
+
+func TestKnownNames(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/location-area":
+			w.Write([]byte(`{"count": 2, "results": [{"name": "canalave-city-area"}, {"name": "eterna-city-area"}]}`))
+		case "/location-area/viridian-forest-area", "/pokemon/pikachu", "/pokemon-species/pikachu":
+			w.Write([]byte(`{"name": "ok"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	ctx := context.Background()
+
+	if names := client.KnownNames("location-area"); len(names) != 0 {
+		t.Errorf("expected no names before any request, got %v", names)
+	}
+	if _, err := client.ListLocationAreas(ctx, ListOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetLocationAreasDetail(ctx, "viridian-forest-area"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetPokemonInformation(ctx, "pikachu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetPokemonSpecies(ctx, "pikachu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Errors aren't cached, so they aren't names
+	client.GetPokemonInformation(ctx, "missingno")
+
+	cases := []struct {
+		resource string
+		expected []string
+	}{
+		{resource: "location-area", expected: []string{"canalave-city-area", "eterna-city-area", "viridian-forest-area"}},
+		{resource: "pokemon", expected: []string{"pikachu"}},
+		{resource: "region", expected: []string{}},
+	}
+	hits := client.Cache().Stats().Hits
+	for _, c := range cases {
+		names := client.KnownNames(c.resource)
+		if strings.Join(names, ",") != strings.Join(c.expected, ",") {
+			t.Errorf("KnownNames(%q) == %v, expected %v", c.resource, names, c.expected)
+		}
+	}
+	// Completing must not look like cache use
+	if actual := client.Cache().Stats().Hits; actual != hits {
+		t.Errorf("KnownNames changed the cache hits from %d to %d", hits, actual)
+	}
+}
+
+func TestKnownNamesFromDiskCache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/region":
+			w.Write([]byte(`{"count": 2, "results": [{"name": "kanto"}, {"name": "johto"}]}`))
+		default:
+			w.Write([]byte(`{"name": "ok"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	dir := t.TempDir()
+	// newRun starts a client on the disk cache kept between runs
+	newRun := func() *Client {
+		cache, err := pokecache.NewDiskCache(dir, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return NewClient(WithBaseURL(server.URL), WithCache(cache))
+	}
+
+	first := newRun()
+	ctx := context.Background()
+	if _, err := first.GetRegionsList(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := first.GetPokemonInformation(ctx, "pikachu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The next run knows the names before making any request
+	requests = 0
+	second := newRun()
+	if names := second.KnownNames("region"); strings.Join(names, ",") != "johto,kanto" {
+		t.Errorf("KnownNames(region) == %v, expected [johto kanto]", names)
+	}
+	if names := second.KnownNames("pokemon"); strings.Join(names, ",") != "pikachu" {
+		t.Errorf("KnownNames(pokemon) == %v, expected [pikachu]", names)
+	}
+	if requests != 0 {
+		t.Errorf("expected no requests, got %d", requests)
+	}
+	if stats := second.Cache().Stats(); stats.Hits != 0 || stats.Misses != 0 {
+		t.Errorf("expected seeding not to count, got %d hits and %d misses", stats.Hits, stats.Misses)
+	}
+}
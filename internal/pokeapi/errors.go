@@ -0,0 +1,49 @@
+// errors.go
+package pokeapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrNotFound means the requested resource doesn't exist, usually a typo
+	ErrNotFound = errors.New("resource not found")
+	// ErrRateLimited means PokeAPI asked us to slow down
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUpstream means PokeAPI failed to serve the request
+	ErrUpstream = errors.New("upstream error")
+	// ErrDecode means the response body wasn't the JSON we expected
+	ErrDecode = errors.New("unexpected response body")
+)
+
+// StatusError is returned when PokeAPI answers with a non-200 status.
+// It matches ErrNotFound, ErrRateLimited or ErrUpstream with errors.Is.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	// RetryAfter is how long the server asked us to wait, if it said
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%v: %s returned status %d", e.Unwrap(), e.URL, e.StatusCode)
+}
+
+func (e *StatusError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	default:
+		return ErrUpstream
+	}
+}
+
+// decodeError wraps a JSON error so it matches ErrDecode
+func decodeError(url string, err error) error {
+	return fmt.Errorf("%w from %s: %w", ErrDecode, url, err)
+}
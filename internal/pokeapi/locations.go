@@ -2,9 +2,16 @@
 package pokeapi
 
 import (
+	"context"
 	"fmt"
+	neturl "net/url"
+	"strconv"
 )
 
+// DefaultPageSize is how many location areas a page holds unless asked
+// otherwise
+const DefaultPageSize = 20
+
 // For listing/pagination (map/mapb commands)
 type LocationAreasListResponse struct {
 	Count    int    `json:"count"`
@@ -71,31 +78,80 @@ type LocationAreasDetailsResponse struct {
 	} `json:"pokemon_encounters"`
 }
 
-func (c *Client) GetLocationAreasList(url string, args []string) (LocationAreasListResponse, error) {
-	if url == "" {
-		url = "https://pokeapi.co/api/v2/location-area?offset=0&limit=20"
+// ListOptions selects a page of a list endpoint
+type ListOptions struct {
+	Offset int
+	// Limit is the page size, DefaultPageSize if 0
+	Limit int
+}
+
+// ParseListOptions reads the page a list URL points at, such as the Next
+// and Previous links of a list response. Missing values are left at zero.
+func ParseListOptions(rawURL string) (ListOptions, error) {
+	parsed, err := neturl.Parse(rawURL)
+	if err != nil {
+		return ListOptions{}, fmt.Errorf("invalid list url %q: %w", rawURL, err)
+	}
+	opts := ListOptions{}
+	query := parsed.Query()
+	if value := query.Get("offset"); value != "" {
+		if opts.Offset, err = strconv.Atoi(value); err != nil || opts.Offset < 0 {
+			return ListOptions{}, fmt.Errorf("invalid offset %q in %s", value, rawURL)
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		if opts.Limit, err = strconv.Atoi(value); err != nil || opts.Limit < 0 {
+			return ListOptions{}, fmt.Errorf("invalid limit %q in %s", value, rawURL)
+		}
+	}
+	return opts, nil
+}
+
+// Page returns the 1-based page number opts points at
+func (opts ListOptions) Page() int {
+	return opts.Offset/opts.limit() + 1
+}
+
+// Pages returns how many pages of opts' size count items fill
+func (opts ListOptions) Pages(count int) int {
+	return (count + opts.limit() - 1) / opts.limit()
+}
+
+func (opts ListOptions) limit() int {
+	if opts.Limit <= 0 {
+		return DefaultPageSize
 	}
-	if len(args) > 0 {
-		url = fmt.Sprintf("https://pokeapi.co/api/v2/location-area/%s", args[0])
+	return opts.Limit
+}
+
+func (opts ListOptions) query() neturl.Values {
+	return neturl.Values{"offset": {strconv.Itoa(opts.Offset)}, "limit": {strconv.Itoa(opts.limit())}}
+}
+
+// ListLocationAreas fetches one page of location areas
+func (c *Client) ListLocationAreas(ctx context.Context, opts ListOptions) (LocationAreasListResponse, error) {
+	if opts.Offset < 0 || opts.Limit < 0 {
+		return LocationAreasListResponse{}, fmt.Errorf("offset and limit must not be negative")
 	}
-	locationAreasDetailsResponse := LocationAreasListResponse{}
-	err := c.fetchJSON(url, &locationAreasDetailsResponse)
+	url := c.endpoint(opts.query(), "location-area")
+	locationAreasListResponse := LocationAreasListResponse{}
+	err := c.fetchJSON(ctx, url, &locationAreasListResponse)
 	if err != nil {
-		return locationAreasDetailsResponse, nil
+		return locationAreasListResponse, err
 	}
-	return locationAreasDetailsResponse, nil
+	return locationAreasListResponse, nil
 }
 
-func (c *Client) GetLocationAreasDetail(locationName string) (LocationAreasDetailsResponse, error) {
+func (c *Client) GetLocationAreasDetail(ctx context.Context, locationName string) (LocationAreasDetailsResponse, error) {
 	if locationName == "" {
 		return LocationAreasDetailsResponse{}, fmt.Errorf("Must supply a location name")
 	}
 
-	url := fmt.Sprintf("https://pokeapi.co/api/v2/location-area/%s", locationName)
+	url := c.endpoint(nil, "location-area", locationName)
 	locationAreasDetailsResponse := LocationAreasDetailsResponse{}
-	err := c.fetchJSON(url, &locationAreasDetailsResponse)
+	err := c.fetchJSON(ctx, url, &locationAreasDetailsResponse)
 	if err != nil {
-		return locationAreasDetailsResponse, nil
+		return locationAreasDetailsResponse, err
 	}
 	return locationAreasDetailsResponse, nil
 }
@@ -0,0 +1,68 @@
+// options.go
+package pokeapi
+
+import (
+	"net/http"
+	"pokedexcli/internal/pokecache"
+	"strings"
+	"time"
+)
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithCache replaces the default in-memory response cache
+func WithCache(cache pokecache.Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithBaseURL points the client at another PokeAPI instance, such as a
+// self-hosted mirror. The URL should include the API version path,
+// e.g. "https://pokeapi.example.com/api/v2".
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithTransport sets the round tripper used for HTTP requests
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.client.Transport = transport
+	}
+}
+
+// WithTimeout sets the timeout for a single HTTP request
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.client.Timeout = timeout
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithRateLimit limits the client to rate requests per second, allowing
+// bursts of up to burst requests. A rate of 0 or less disables limiting.
+func WithRateLimit(rate float64, burst int) Option {
+	return func(c *Client) {
+		if rate <= 0 {
+			c.limiter = nil
+			return
+		}
+		c.limiter = newRateLimiter(rate, burst)
+	}
+}
@@ -2,8 +2,10 @@
 package pokeapi
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 )
 
@@ -291,39 +293,107 @@ type PokemonResponse struct {
 	} `json:"past_abilities"`
 }
 
-func (c *Client) GetPokemonInformation(pokemonName string) (PokemonResponse, error) {
+func (c *Client) GetPokemonInformation(ctx context.Context, pokemonName string) (PokemonResponse, error) {
 	if pokemonName == "" {
 		return PokemonResponse{}, fmt.Errorf("must supply a pokemon name")
 	}
-	url := fmt.Sprintf("https://pokeapi.co/api/v2/pokemon/%s", pokemonName)
+	url := c.endpoint(nil, "pokemon", pokemonName)
 	pokemonRepsonse := PokemonResponse{}
-	err := c.fetchJSON(url, &pokemonRepsonse)
+	err := c.fetchJSON(ctx, url, &pokemonRepsonse)
 	if err != nil {
-		return pokemonRepsonse, nil
+		return pokemonRepsonse, err
 	}
 	return pokemonRepsonse, nil
 }
 
-// Print min and max values for baseExperience.
-func (c *Client) GetAllPokemonBaseExperienceStats() error {
-	maxPokemonCount := 1025
-	min := math.MaxInt32
-	max := 0
-	for i := 1; i <= maxPokemonCount; i++ { // ++ is a statement.
-		fmt.Printf("Checking Pokemon ID: %d stats...\n", i)
-		url := fmt.Sprintf("https://pokeapi.co/api/v2/pokemon/%s", strconv.Itoa(i))
-		pokemonRepsonse := PokemonResponse{}
-		err := c.fetchJSON(url, &pokemonRepsonse)
-		if err != nil {
-			return fmt.Errorf("unexpected fetchJSON error: %w", err)
-		}
-		if pokemonRepsonse.BaseExperience < min {
-			min = pokemonRepsonse.BaseExperience
+// NationalDexSize is the number of species in the National Pokédex as
+// of Generation IX
+const NationalDexSize = 1025
+
+// BaseExperienceStats summarises base experience across many Pokemon
+type BaseExperienceStats struct {
+	// Count is the number of Pokemon that have a base experience
+	Count int
+	Min   int
+	Max   int
+	Mean  float64
+	P5    float64
+	P25   float64
+	P50   float64
+	P75   float64
+	P95   float64
+	// Missing lists IDs whose base experience PokeAPI leaves null
+	Missing []int
+	// Failed lists IDs that couldn't be fetched
+	Failed []int
+}
+
+// GetBaseExperienceStats fetches Pokemon 1 through count concurrently and
+// summarises their base experience. Pokemon that fail to load are listed
+// in Failed and the stats are computed from the rest; the error is only
+// non-nil if count is below 1, nothing could be fetched or ctx was
+// cancelled.
+func (c *Client) GetBaseExperienceStats(ctx context.Context, count int, opts BulkOptions) (BaseExperienceStats, error) {
+	if count < 1 {
+		return BaseExperienceStats{}, fmt.Errorf("count must be at least 1, got %d", count)
+	}
+	ids := make([]int, count)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+
+	results, err := FetchAll(ctx, ids, func(ctx context.Context, id int) (PokemonResponse, error) {
+		return c.GetPokemonInformation(ctx, strconv.Itoa(id))
+	}, opts)
+	if ctx.Err() != nil {
+		return BaseExperienceStats{}, ctx.Err()
+	}
+
+	stats := BaseExperienceStats{}
+	values := []int{}
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			stats.Failed = append(stats.Failed, result.Key)
+		case result.Value.BaseExperience == 0:
+			stats.Missing = append(stats.Missing, result.Key)
+		default:
+			values = append(values, result.Value.BaseExperience)
 		}
-		if pokemonRepsonse.BaseExperience > max {
-			max = pokemonRepsonse.BaseExperience
+	}
+	if len(values) == 0 {
+		if err != nil {
+			return stats, err
 		}
-		fmt.Printf("GetAllPokemonBaseExperienceStats: min: %d, max: %d\n", min, max)
+		return stats, fmt.Errorf("no base experience values found")
+	}
+
+	sort.Ints(values)
+	sum := 0
+	for _, v := range values {
+		sum += v
+	}
+	stats.Count = len(values)
+	stats.Min = values[0]
+	stats.Max = values[len(values)-1]
+	stats.Mean = float64(sum) / float64(len(values))
+	stats.P5 = percentile(values, 5)
+	stats.P25 = percentile(values, 25)
+	stats.P50 = percentile(values, 50)
+	stats.P75 = percentile(values, 75)
+	stats.P95 = percentile(values, 95)
+	return stats, nil
+}
+
+// percentile returns the p-th percentile of sorted values, interpolating
+// linearly between the closest ranks
+func percentile(sorted []int, p float64) float64 {
+	if len(sorted) == 1 {
+		return float64(sorted[0])
 	}
-	return nil
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	weight := rank - float64(lower)
+	return float64(sorted[lower])*(1-weight) + float64(sorted[upper])*weight
 }
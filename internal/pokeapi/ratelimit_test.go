@@ -0,0 +1,89 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurst(t *testing.T) {
+	limiter := newRateLimiter(1, 3)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("expected burst to pass without waiting, took %v", elapsed)
+	}
+}
+
+func TestRateLimiterRate(t *testing.T) {
+	limiter := newRateLimiter(50, 1)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// One token up front, then four at 20ms each
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("expected requests to be spaced out, took only %v", elapsed)
+	}
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	limiter := newRateLimiter(1, 1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected queued request to give up with its context, took %v", elapsed)
+	}
+
+	// The abandoned reservation is returned, so the bucket is back near
+	// empty rather than a token in debt
+	limiter.mutex.Lock()
+	tokens := limiter.tokens
+	limiter.mutex.Unlock()
+	if tokens < -0.5 {
+		t.Errorf("expected cancelled wait to return its token, bucket at %v", tokens)
+	}
+}
+
+func TestClientRateLimit(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}), WithRateLimit(20, 1))
+
+	start := time.Now()
+	for _, name := range []string{"bulbasaur", "ivysaur", "venusaur"} {
+		if _, err := client.GetPokemonInformation(context.Background(), name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("expected requests to be rate limited, took only %v", elapsed)
+	}
+
+	// Cache hits don't count against the limit
+	start = time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := client.GetPokemonInformation(context.Background(), "bulbasaur"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 40*time.Millisecond {
+		t.Errorf("expected cached requests to skip the limiter, took %v", elapsed)
+	}
+}
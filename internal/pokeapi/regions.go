@@ -0,0 +1,86 @@
+// regions.go
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+	neturl "net/url"
+)
+
+// RegionsListResponse lists every region, there are only a handful
+type RegionsListResponse struct {
+	Count   int `json:"count"`
+	Results []struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"results"`
+}
+
+// RegionResponse is a region, like kanto, and the locations in it
+type RegionResponse struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	MainGeneration struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"main_generation"`
+	Locations []struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"locations"`
+	VersionGroups []struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"version_groups"`
+}
+
+// LocationResponse is a location, like a route or a city, and the areas
+// it is split into
+type LocationResponse struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Region struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"region"`
+	Areas []struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"areas"`
+}
+
+func (c *Client) GetRegionsList(ctx context.Context) (RegionsListResponse, error) {
+	url := c.endpoint(neturl.Values{"limit": {"100"}}, "region")
+	regionsListResponse := RegionsListResponse{}
+	err := c.fetchJSON(ctx, url, &regionsListResponse)
+	if err != nil {
+		return regionsListResponse, err
+	}
+	return regionsListResponse, nil
+}
+
+func (c *Client) GetRegion(ctx context.Context, regionName string) (RegionResponse, error) {
+	if regionName == "" {
+		return RegionResponse{}, fmt.Errorf("must supply a region name")
+	}
+	url := c.endpoint(nil, "region", regionName)
+	regionResponse := RegionResponse{}
+	err := c.fetchJSON(ctx, url, &regionResponse)
+	if err != nil {
+		return regionResponse, err
+	}
+	return regionResponse, nil
+}
+
+func (c *Client) GetLocation(ctx context.Context, locationName string) (LocationResponse, error) {
+	if locationName == "" {
+		return LocationResponse{}, fmt.Errorf("must supply a location name")
+	}
+	url := c.endpoint(nil, "location", locationName)
+	locationResponse := LocationResponse{}
+	err := c.fetchJSON(ctx, url, &locationResponse)
+	if err != nil {
+		return locationResponse, err
+	}
+	return locationResponse, nil
+}
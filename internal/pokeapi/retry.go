@@ -0,0 +1,133 @@
+// retry.go
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. Only rate
+// limiting, server errors and timeouts are retried; every request the
+// client makes is an idempotent GET, so retrying is always safe.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry; it doubles after each
+	// attempt, with jitter
+	BaseDelay time.Duration
+	// MaxDelay caps the wait between two attempts
+	MaxDelay time.Duration
+	// Budget caps the total time spent on a request including waits;
+	// 0 means no cap
+	Budget time.Duration
+}
+
+// DefaultRetryPolicy retries a couple of times without keeping the
+// player waiting for long
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    4 * time.Second,
+	Budget:      15 * time.Second,
+}
+
+// getWithRetry calls get until it succeeds, fails permanently or the
+// retry policy is exhausted
+func (c *Client) getWithRetry(ctx context.Context, url string) ([]byte, error) {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		body, err := c.get(ctx, url)
+		if err == nil {
+			return body, nil
+		}
+		if attempt >= c.retry.MaxAttempts || !retryable(ctx, err) {
+			return nil, err
+		}
+
+		delay := c.retry.backoff(attempt)
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			delay = statusErr.RetryAfter
+		}
+		// Give up now rather than sleep past the budget
+		if c.retry.Budget > 0 && time.Since(start)+delay > c.retry.Budget {
+			return nil, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the jittered wait after the given attempt
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	// Double without overflowing for large attempt counts
+	for i := 1; i < attempt && delay < math.MaxInt64/2; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	// Equal jitter: wait at least half the delay so retries still back
+	// off, but spread them out so clients don't retry in lockstep
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// retryable reports whether err is worth another attempt
+func retryable(ctx context.Context, err error) bool {
+	// The caller gave up, don't keep going
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// parseRetryAfter reads a Retry-After header, given either as seconds or
+// as an HTTP date. It returns 0 when the header is missing or invalid.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
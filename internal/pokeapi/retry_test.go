@@ -0,0 +1,170 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetries keeps retry tests quick
+var fastRetries = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   time.Millisecond,
+	MaxDelay:    10 * time.Millisecond,
+}
+
+// flakyHandler fails with status for the first failures requests, then
+// serves body
+func flakyHandler(failures int32, status int, header http.Header, body string, calls *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(body))
+	})
+}
+
+func TestRetryRecoversFromServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, flakyHandler(2, http.StatusServiceUnavailable, nil, `{"id": 25, "name": "pikachu"}`, &calls),
+		WithRetryPolicy(fastRetries))
+
+	pokemon, err := client.GetPokemonInformation(context.Background(), "pikachu")
+	if err != nil {
+		t.Fatalf("expected retries to succeed, got %v", err)
+	}
+	if pokemon.ID != 25 {
+		t.Errorf("unexpected pokemon: %+v", pokemon)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls.Load())
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, flakyHandler(10, http.StatusInternalServerError, nil, `{}`, &calls),
+		WithRetryPolicy(fastRetries))
+
+	_, err := client.GetPokemonInformation(context.Background(), "pikachu")
+	if !errors.Is(err, ErrUpstream) {
+		t.Fatalf("expected upstream error, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls.Load())
+	}
+}
+
+func TestRetrySkipsPermanentErrors(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, flakyHandler(10, http.StatusNotFound, nil, `{}`, &calls),
+		WithRetryPolicy(fastRetries))
+
+	if _, err := client.GetPokemonInformation(context.Background(), "pikachuu"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected a single attempt, got %d", calls.Load())
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	header := http.Header{"Retry-After": {"1"}}
+	client := newTestClient(t, flakyHandler(1, http.StatusTooManyRequests, header, `{"id": 25}`, &calls),
+		WithRetryPolicy(fastRetries))
+
+	start := time.Now()
+	if _, err := client.GetPokemonInformation(context.Background(), "pikachu"); err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("expected to wait for Retry-After, only waited %v", elapsed)
+	}
+}
+
+func TestRetryRespectsBudget(t *testing.T) {
+	var calls atomic.Int32
+	header := http.Header{"Retry-After": {"30"}}
+	policy := fastRetries
+	policy.Budget = 100 * time.Millisecond
+	client := newTestClient(t, flakyHandler(1, http.StatusTooManyRequests, header, `{}`, &calls),
+		WithRetryPolicy(policy))
+
+	start := time.Now()
+	if _, err := client.GetPokemonInformation(context.Background(), "pikachu"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected rate limited error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected to give up instead of waiting, took %v", elapsed)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected a single attempt, got %d", calls.Load())
+	}
+}
+
+func TestRetryOnTimeout(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.Write([]byte(`{"id": 25}`))
+	}), WithTimeout(20*time.Millisecond), WithRetryPolicy(fastRetries))
+
+	if _, err := client.GetPokemonInformation(context.Background(), "pikachu"); err != nil {
+		t.Fatalf("expected retry after timeout to succeed, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		header   string
+		expected time.Duration
+	}{
+		{header: "", expected: 0},
+		{header: "5", expected: 5 * time.Second},
+		{header: "-1", expected: 0},
+		{header: "Wed, 01 Jan 2025 12:00:30 GMT", expected: 30 * time.Second},
+		{header: "Wed, 01 Jan 2025 11:00:00 GMT", expected: 0},
+		{header: "soon", expected: 0},
+	}
+	for _, c := range cases {
+		if actual := parseRetryAfter(c.header, now); actual != c.expected {
+			t.Errorf("parseRetryAfter(%q) == %v, expected %v", c.header, actual, c.expected)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	cases := []struct {
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{attempt: 1, min: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{attempt: 2, min: 100 * time.Millisecond, max: 200 * time.Millisecond},
+		{attempt: 3, min: 150 * time.Millisecond, max: 300 * time.Millisecond},
+		{attempt: 40, min: 150 * time.Millisecond, max: 300 * time.Millisecond},
+	}
+	for _, c := range cases {
+		for i := 0; i < 20; i++ {
+			delay := policy.backoff(c.attempt)
+			if delay < c.min || delay > c.max {
+				t.Errorf("backoff(%d) == %v, expected between %v and %v", c.attempt, delay, c.min, c.max)
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// species.go
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+	neturl "net/url"
+)
+
+// PokemonSpeciesResponse holds the species data shared by all forms of a
+// Pokemon
+type PokemonSpeciesResponse struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Order         int    `json:"order"`
+	CaptureRate   int    `json:"capture_rate"`
+	BaseHappiness int    `json:"base_happiness"`
+	GenderRate    int    `json:"gender_rate"`
+	HatchCounter  int    `json:"hatch_counter"`
+	IsBaby        bool   `json:"is_baby"`
+	IsLegendary   bool   `json:"is_legendary"`
+	IsMythical    bool   `json:"is_mythical"`
+	GrowthRate    struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"growth_rate"`
+	Generation struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"generation"`
+}
+
+func (c *Client) GetPokemonSpecies(ctx context.Context, speciesName string) (PokemonSpeciesResponse, error) {
+	if speciesName == "" {
+		return PokemonSpeciesResponse{}, fmt.Errorf("must supply a species name")
+	}
+	url := c.endpoint(nil, "pokemon-species", speciesName)
+	speciesResponse := PokemonSpeciesResponse{}
+	err := c.fetchJSON(ctx, url, &speciesResponse)
+	if err != nil {
+		return speciesResponse, err
+	}
+	return speciesResponse, nil
+}
+
+// GetPokemonSpeciesCount returns the number of species PokeAPI knows about,
+// which grows as new generations are added
+func (c *Client) GetPokemonSpeciesCount(ctx context.Context) (int, error) {
+	url := c.endpoint(neturl.Values{"limit": {"1"}}, "pokemon-species")
+	listResponse := struct {
+		Count int `json:"count"`
+	}{}
+	err := c.fetchJSON(ctx, url, &listResponse)
+	if err != nil {
+		return 0, err
+	}
+	return listResponse.Count, nil
+}
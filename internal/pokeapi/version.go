@@ -0,0 +1,73 @@
+// version.go
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// VersionResponse is a single game, like red or heartgold
+type VersionResponse struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	VersionGroup struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"version_group"`
+}
+
+func (c *Client) GetVersion(ctx context.Context, versionName string) (VersionResponse, error) {
+	if versionName == "" {
+		return VersionResponse{}, fmt.Errorf("must supply a version name")
+	}
+	url := c.endpoint(nil, "version", versionName)
+	versionResponse := VersionResponse{}
+	err := c.fetchJSON(ctx, url, &versionResponse)
+	if err != nil {
+		return versionResponse, err
+	}
+	return versionResponse, nil
+}
+
+// Sprite returns the front sprite the Pokemon had in a game version,
+// falling back to the default sprite for versions without their own
+func (p PokemonResponse) Sprite(version string) string {
+	versions := p.Sprites.Versions
+	sprite := ""
+	switch version {
+	case "red", "blue":
+		sprite = versions.GenerationI.RedBlue.FrontDefault
+	case "yellow":
+		sprite = versions.GenerationI.Yellow.FrontDefault
+	case "gold":
+		sprite = versions.GenerationIi.Gold.FrontDefault
+	case "silver":
+		sprite = versions.GenerationIi.Silver.FrontDefault
+	case "crystal":
+		sprite = versions.GenerationIi.Crystal.FrontDefault
+	case "ruby", "sapphire":
+		sprite = versions.GenerationIii.RubySapphire.FrontDefault
+	case "emerald":
+		sprite = versions.GenerationIii.Emerald.FrontDefault
+	case "firered", "leafgreen":
+		sprite = versions.GenerationIii.FireredLeafgreen.FrontDefault
+	case "diamond", "pearl":
+		sprite = versions.GenerationIv.DiamondPearl.FrontDefault
+	case "platinum":
+		sprite = versions.GenerationIv.Platinum.FrontDefault
+	case "heartgold", "soulsilver":
+		sprite = versions.GenerationIv.HeartgoldSoulsilver.FrontDefault
+	case "black", "white", "black-2", "white-2":
+		sprite = versions.GenerationV.BlackWhite.FrontDefault
+	case "x", "y":
+		sprite = versions.GenerationVi.XY.FrontDefault
+	case "omega-ruby", "alpha-sapphire":
+		sprite = versions.GenerationVi.OmegarubyAlphasapphire.FrontDefault
+	case "ultra-sun", "ultra-moon":
+		sprite = versions.GenerationVii.UltraSunUltraMoon.FrontDefault
+	}
+	if sprite == "" {
+		return p.Sprites.FrontDefault
+	}
+	return sprite
+}
@@ -0,0 +1,160 @@
+package pokecache
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// runConformance checks the behaviour every Cache implementation must share
+func runConformance(t *testing.T, newCache func(t *testing.T) Cache) {
+	t.Run("missing key", func(t *testing.T) {
+		cache := newCache(t)
+		if _, ok := cache.Get("https://example.com/missing"); ok {
+			t.Errorf("expected missing key to be a miss")
+		}
+	})
+
+	t.Run("add then get", func(t *testing.T) {
+		cache := newCache(t)
+		cache.Add("https://example.com", []byte("testdata"))
+		val, ok := cache.Get("https://example.com")
+		if !ok {
+			t.Fatalf("expected to find key")
+		}
+		if string(val) != "testdata" {
+			t.Errorf("expected %q, got %q", "testdata", val)
+		}
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		cache := newCache(t)
+		cache.Add("https://example.com", []byte("old"))
+		cache.Add("https://example.com", []byte("new"))
+		val, ok := cache.Get("https://example.com")
+		if !ok || string(val) != "new" {
+			t.Errorf("expected overwritten value %q, got %q", "new", val)
+		}
+	})
+
+	t.Run("keys are distinct", func(t *testing.T) {
+		cache := newCache(t)
+		cache.Add("https://example.com/a", []byte("a"))
+		cache.Add("https://example.com/b", []byte("b"))
+		a, _ := cache.Get("https://example.com/a")
+		b, _ := cache.Get("https://example.com/b")
+		if string(a) != "a" || string(b) != "b" {
+			t.Errorf("expected a/b, got %q/%q", a, b)
+		}
+	})
+
+	t.Run("binary values", func(t *testing.T) {
+		cache := newCache(t)
+		val := []byte{0, 1, 2, 255, '\n'}
+		cache.Add("https://example.com/bin", val)
+		got, ok := cache.Get("https://example.com/bin")
+		if !ok || string(got) != string(val) {
+			t.Errorf("expected %v, got %v", val, got)
+		}
+	})
+
+	t.Run("keys and clear", func(t *testing.T) {
+		cache := newCache(t)
+		cache.Add("https://example.com/a", []byte("a"))
+		cache.Add("https://example.com/b", []byte("b"))
+		keys := cache.Keys()
+		sort.Strings(keys)
+		if len(keys) != 2 || keys[0] != "https://example.com/a" || keys[1] != "https://example.com/b" {
+			t.Errorf("expected both keys, got %v", keys)
+		}
+
+		cache.Clear()
+		if keys := cache.Keys(); len(keys) != 0 {
+			t.Errorf("expected no keys after Clear, got %v", keys)
+		}
+		if _, ok := cache.Get("https://example.com/a"); ok {
+			t.Errorf("expected miss after Clear")
+		}
+	})
+
+	t.Run("peek", func(t *testing.T) {
+		cache := newCache(t)
+		cache.Add("https://pokeapi.co/api/v2/pokemon/pikachu", []byte("12345"))
+		val, ok := cache.Peek("https://pokeapi.co/api/v2/pokemon/pikachu")
+		if !ok || string(val) != "12345" {
+			t.Errorf("expected %q, got %q", "12345", val)
+		}
+		if _, ok := cache.Peek("https://pokeapi.co/api/v2/pokemon/missingno"); ok {
+			t.Errorf("expected missing key to be a miss")
+		}
+		if stats := cache.Stats(); stats.Hits != 0 || stats.Misses != 0 {
+			t.Errorf("expected Peek not to count, got %d hits and %d misses", stats.Hits, stats.Misses)
+		}
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		cache := newCache(t)
+		cache.Add("https://pokeapi.co/api/v2/pokemon/pikachu", []byte("12345"))
+		cache.Add("https://pokeapi.co/api/v2/location-area/canalave-city-area", []byte("123"))
+		cache.Get("https://pokeapi.co/api/v2/pokemon/pikachu")
+		cache.Get("https://pokeapi.co/api/v2/pokemon/pikachu")
+		cache.Get("https://pokeapi.co/api/v2/pokemon/missingno")
+		cache.Get("https://pokeapi.co/api/v2/location-area/canalave-city-area")
+
+		stats := cache.Stats()
+		if stats.Hits != 3 || stats.Misses != 1 {
+			t.Errorf("expected 3 hits and 1 miss, got %d and %d", stats.Hits, stats.Misses)
+		}
+		if stats.Entries != 2 || stats.Bytes != 8 {
+			t.Errorf("expected 2 entries of 8 bytes, got %d and %d", stats.Entries, stats.Bytes)
+		}
+		pokemon := stats.Groups["pokemon"]
+		if pokemon.Hits != 2 || pokemon.Misses != 1 || pokemon.Entries != 1 || pokemon.Bytes != 5 {
+			t.Errorf("unexpected pokemon group stats: %+v", pokemon)
+		}
+		area := stats.Groups["location-area"]
+		if area.Hits != 1 || area.Entries != 1 || area.Bytes != 3 {
+			t.Errorf("unexpected location-area group stats: %+v", area)
+		}
+	})
+
+	t.Run("concurrent access", func(t *testing.T) {
+		cache := newCache(t)
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				key := fmt.Sprintf("https://example.com/%d", i)
+				for j := 0; j < 20; j++ {
+					cache.Add(key, []byte(key))
+					if val, ok := cache.Get(key); !ok || string(val) != key {
+						t.Errorf("expected %q, got %q", key, val)
+						return
+					}
+				}
+			}(i)
+		}
+		wg.Wait()
+	})
+}
+
+func TestMemoryCacheConformance(t *testing.T) {
+	runConformance(t, func(t *testing.T) Cache {
+		cache := NewCache(time.Minute)
+		t.Cleanup(cache.Stop)
+		return cache
+	})
+}
+
+func TestDiskCacheConformance(t *testing.T) {
+	runConformance(t, func(t *testing.T) Cache {
+		cache, err := NewDiskCache(t.TempDir(), time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cache
+	})
+}
@@ -0,0 +1,160 @@
+// disk.go
+package pokecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DiskCache is a Cache that stores one file per key in a directory, so
+// cached responses survive restarts
+type DiskCache struct {
+	dir      string
+	ttl      time.Duration
+	counters counters
+}
+
+// diskEntry is the on-disk layout of a single cached value
+type diskEntry struct {
+	Key       string    `json:"key"`
+	CreatedAt time.Time `json:"created_at"`
+	Val       []byte    `json:"val"`
+}
+
+// NewDiskCache creates dir if needed and returns a cache stored in it.
+// Entries older than ttl are treated as missing; a ttl of 0 keeps
+// entries forever.
+func NewDiskCache(dir string, ttl time.Duration) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating cache dir: %w", err)
+	}
+	return &DiskCache{dir: dir, ttl: ttl}, nil
+}
+
+// path returns the file holding key. Keys are URLs, so they are hashed
+// into safe file names.
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *DiskCache) Add(key string, val []byte) {
+	data, err := json.Marshal(diskEntry{
+		Key:       key,
+		CreatedAt: time.Now(),
+		Val:       val,
+	})
+	if err != nil {
+		return
+	}
+
+	// Write to a temp file and rename it into place so concurrent readers
+	// never see a partial entry. A failed write just means a cache miss
+	// later, so errors are dropped.
+	tmp, err := os.CreateTemp(c.dir, ".entry-*.tmp")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+	os.Rename(tmp.Name(), c.path(key))
+}
+
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	path := c.path(key)
+	entry, err := readDiskEntry(path)
+	if err != nil || entry.Key != key {
+		c.counters.miss(key)
+		return []byte{}, false
+	}
+	if c.expired(entry) {
+		os.Remove(path)
+		c.counters.expire(key)
+		c.counters.miss(key)
+		return []byte{}, false
+	}
+	c.counters.hit(key)
+	return entry.Val, true
+}
+
+func (c *DiskCache) Peek(key string) ([]byte, bool) {
+	entry, err := readDiskEntry(c.path(key))
+	if err != nil || entry.Key != key || c.expired(entry) {
+		return []byte{}, false
+	}
+	return entry.Val, true
+}
+
+// Keys returns the stored keys in sorted order. Expired entries found
+// along the way are removed.
+func (c *DiskCache) Keys() []string {
+	keys := []string{}
+	c.walk(func(entry diskEntry) {
+		keys = append(keys, entry.Key)
+	})
+	sort.Strings(keys)
+	return keys
+}
+
+func (c *DiskCache) Clear() {
+	paths, _ := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	for _, path := range paths {
+		os.Remove(path)
+	}
+}
+
+func (c *DiskCache) Stats() Stats {
+	sizes := map[string]int{}
+	c.walk(func(entry diskEntry) {
+		sizes[entry.Key] = len(entry.Val)
+	})
+	return c.counters.stats(sizes)
+}
+
+func (c *DiskCache) expired(entry diskEntry) bool {
+	return c.ttl > 0 && time.Since(entry.CreatedAt) > c.ttl
+}
+
+// walk calls fn for every live entry, removing corrupt and expired files
+func (c *DiskCache) walk(fn func(entry diskEntry)) {
+	paths, _ := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	for _, path := range paths {
+		entry, err := readDiskEntry(path)
+		if err != nil {
+			continue
+		}
+		if c.expired(entry) {
+			os.Remove(path)
+			c.counters.expire(entry.Key)
+			continue
+		}
+		fn(entry)
+	}
+}
+
+// readDiskEntry decodes the entry stored at path. Corrupt files are
+// removed so they don't keep failing.
+func readDiskEntry(path string) (diskEntry, error) {
+	entry := diskEntry{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return entry, err
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		os.Remove(path)
+		return entry, err
+	}
+	return entry, nil
+}
@@ -0,0 +1,39 @@
+package pokecache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiskCachePersists(t *testing.T) {
+	dir := t.TempDir()
+	first, err := NewDiskCache(dir, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.Add("https://example.com", []byte("testdata"))
+
+	// A fresh cache over the same directory sees the entry
+	second, err := NewDiskCache(dir, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	val, ok := second.Get("https://example.com")
+	if !ok || string(val) != "testdata" {
+		t.Errorf("expected entry to survive reopening, got %q", val)
+	}
+}
+
+func TestDiskCacheExpiry(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir(), 5*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Add("https://example.com", []byte("testdata"))
+
+	time.Sleep(10 * time.Millisecond)
+
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected expired entry to be a miss")
+	}
+}
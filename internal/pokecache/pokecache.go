@@ -2,66 +2,226 @@
 package pokecache
 
 import (
+	"container/list"
 	"sync"
 	"time"
 )
 
-type Cache struct {
-	entries  map[string]CacheEntry
-	mutex    sync.RWMutex
-	inverval time.Duration
+// Cache stores raw API responses keyed by URL
+type Cache interface {
+	Add(key string, val []byte)
+	Get(key string) ([]byte, bool)
+	// Peek is Get without side effects: it isn't counted in Stats and
+	// doesn't make the entry recently used
+	Peek(key string) ([]byte, bool)
+	// Keys lists the stored keys
+	Keys() []string
+	// Clear removes every entry; counters are kept
+	Clear()
+	Stats() Stats
+}
+
+// MemoryCache is an in-memory Cache; its contents are lost when the
+// process exits. Entries expire once they are older than the interval,
+// and the least recently used entries are evicted when a size bound is set.
+type MemoryCache struct {
+	entries  map[string]*list.Element
+	order    *list.List // front is most recently used
+	mutex    sync.Mutex
+	interval time.Duration
+
+	maxEntries int
+	maxBytes   int
+	bytes      int
+
+	counters counters
+
+	done     chan struct{}
+	stopOnce sync.Once
 }
 
 type CacheEntry struct {
+	key       string
 	createdAt time.Time
 	val       []byte
 }
 
-func NewCache(inverval time.Duration) *Cache {
-	c := Cache{
-		entries:  make(map[string]CacheEntry),
-		inverval: inverval,
+// Option configures a MemoryCache
+type Option func(*MemoryCache)
+
+// WithMaxEntries bounds the number of entries; 0 means unbounded
+func WithMaxEntries(n int) Option {
+	return func(c *MemoryCache) {
+		c.maxEntries = n
+	}
+}
+
+// WithMaxBytes bounds the total size of cached values; 0 means unbounded
+func WithMaxBytes(n int) Option {
+	return func(c *MemoryCache) {
+		c.maxBytes = n
+	}
+}
+
+// NewCache returns a cache whose entries expire after interval. A
+// background goroutine removes expired entries every interval until
+// Stop is called.
+func NewCache(interval time.Duration, opts ...Option) *MemoryCache {
+	c := &MemoryCache{
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		interval: interval,
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
 	go c.reapLoop()
-	// log.Printf("NewCache: %v interval created\n", inverval)
-	return &c
+	return c
+}
+
+// Stop ends the reaper goroutine. The cache stays usable afterwards,
+// expired entries are just no longer removed in the background.
+func (c *MemoryCache) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.done)
+	})
 }
 
-func (c *Cache) reapLoop() {
-	ticker := time.NewTicker(c.inverval)
+func (c *MemoryCache) reapLoop() {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
 	for {
-		<-ticker.C
-		// log.Printf("reapLoop: %v interval passed.\n", c.inverval)
-		for key, cacheEntry := range c.entries {
-			// if expired, delete
-			if time.Now().After(cacheEntry.createdAt) {
-				// log.Printf("reapLoop: deleted old entry at key: %v\n", key)
-				delete(c.entries, key)
-			}
+		select {
+		case <-c.done:
+			return
+		case now := <-ticker.C:
+			c.reap(now)
 		}
 	}
 }
 
-func (c *Cache) Add(key string, val []byte) {
-	// log.Printf("Add: key %v\n", key, val)
+// reap deletes every entry that has outlived the interval
+func (c *MemoryCache) reap(now time.Time) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	c.entries[key] = CacheEntry{
+	for _, elem := range c.entries {
+		entry := elem.Value.(*CacheEntry)
+		if c.expired(entry, now) {
+			c.removeElement(elem)
+			c.counters.expire(entry.key)
+		}
+	}
+}
+
+func (c *MemoryCache) expired(entry *CacheEntry, now time.Time) bool {
+	return now.Sub(entry.createdAt) >= c.interval
+}
+
+func (c *MemoryCache) Add(key string, val []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if elem, exists := c.entries[key]; exists {
+		c.removeElement(elem)
+	}
+	// A value that can never fit would only flush everything else
+	if c.maxBytes > 0 && len(val) > c.maxBytes {
+		return
+	}
+
+	entry := &CacheEntry{
+		key:       key,
 		createdAt: time.Now(),
 		val:       val,
 	}
+	c.entries[key] = c.order.PushFront(entry)
+	c.bytes += len(val)
+
+	// Evict least recently used entries until we're back within bounds
+	for c.overLimit() {
+		evicted := c.order.Back()
+		c.removeElement(evicted)
+		c.counters.evict(evicted.Value.(*CacheEntry).key)
+	}
 }
 
-func (c *Cache) Get(key string) ([]byte, bool) {
-	// log.Printf("Get: key %v\n", key)
-	// Lock the resource specifically to read only actions.
-	// This allows other reads to happen
-	// Once a mutex.Lock() is called, RLock() operations are blocked
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-	cacheEntry, cacheEntryExists := c.entries[key]
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	// Get updates recency, so it needs the write lock too
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	elem, cacheEntryExists := c.entries[key]
 	if !cacheEntryExists {
+		c.counters.miss(key)
+		return []byte{}, false
+	}
+	entry := elem.Value.(*CacheEntry)
+	// Don't serve entries the reaper hasn't got to yet
+	if c.expired(entry, time.Now()) {
+		c.removeElement(elem)
+		c.counters.expire(key)
+		c.counters.miss(key)
 		return []byte{}, false
 	}
-	return cacheEntry.val, true
+	c.order.MoveToFront(elem)
+	c.counters.hit(key)
+	return entry.val, true
+}
+
+func (c *MemoryCache) Peek(key string) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	elem, exists := c.entries[key]
+	if !exists {
+		return []byte{}, false
+	}
+	entry := elem.Value.(*CacheEntry)
+	if c.expired(entry, time.Now()) {
+		return []byte{}, false
+	}
+	return entry.val, true
+}
+
+// Keys returns the stored keys, most recently used first
+func (c *MemoryCache) Keys() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	keys := make([]string, 0, c.order.Len())
+	for elem := c.order.Front(); elem != nil; elem = elem.Next() {
+		keys = append(keys, elem.Value.(*CacheEntry).key)
+	}
+	return keys
+}
+
+func (c *MemoryCache) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	c.bytes = 0
+}
+
+func (c *MemoryCache) Stats() Stats {
+	c.mutex.Lock()
+	sizes := make(map[string]int, len(c.entries))
+	for key, elem := range c.entries {
+		sizes[key] = len(elem.Value.(*CacheEntry).val)
+	}
+	c.mutex.Unlock()
+	return c.counters.stats(sizes)
+}
+
+func (c *MemoryCache) overLimit() bool {
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		return true
+	}
+	return c.maxBytes > 0 && c.bytes > c.maxBytes
+}
+
+// removeElement deletes an entry; the caller must hold the mutex
+func (c *MemoryCache) removeElement(elem *list.Element) {
+	entry := elem.Value.(*CacheEntry)
+	c.order.Remove(elem)
+	delete(c.entries, entry.key)
+	c.bytes -= len(entry.val)
 }
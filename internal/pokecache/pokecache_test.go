@@ -25,6 +25,7 @@ func TestAddGet(t *testing.T) {
 	for i, c := range cases {
 		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
 			cache := NewCache(interval)
+			defer cache.Stop()
 			cache.Add(c.key, c.val)
 			val, ok := cache.Get(c.key)
 			if !ok {
@@ -43,6 +44,7 @@ func TestReapLoop(t *testing.T) {
 	const baseTime = 5 * time.Millisecond
 	const waitTime = baseTime + 5*time.Millisecond
 	cache := NewCache(baseTime)
+	defer cache.Stop()
 	cache.Add("https://example.com", []byte("testdata"))
 
 	_, ok := cache.Get("https://example.com")
@@ -59,3 +61,114 @@ func TestReapLoop(t *testing.T) {
 		return
 	}
 }
+
+func TestExpiryUsesEntryAge(t *testing.T) {
+	const interval = 100 * time.Millisecond
+	cache := NewCache(interval)
+	defer cache.Stop()
+
+	cache.Add("https://example.com/old", []byte("old"))
+	time.Sleep(60 * time.Millisecond)
+	cache.Add("https://example.com/new", []byte("new"))
+	time.Sleep(60 * time.Millisecond)
+
+	if _, ok := cache.Get("https://example.com/old"); ok {
+		t.Errorf("expected entry older than the interval to expire")
+	}
+	if _, ok := cache.Get("https://example.com/new"); !ok {
+		t.Errorf("expected entry younger than the interval to survive")
+	}
+}
+
+func TestMaxEntriesEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewCache(time.Minute, WithMaxEntries(2))
+	defer cache.Stop()
+
+	cache.Add("a", []byte("a"))
+	cache.Add("b", []byte("b"))
+	// Touch a so b becomes the least recently used
+	cache.Get("a")
+	cache.Add("c", []byte("c"))
+
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("expected b to be evicted")
+	}
+	if evictions := cache.Stats().Evictions; evictions != 1 {
+		t.Errorf("expected 1 eviction, got %d", evictions)
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("expected %s to be kept", key)
+		}
+	}
+}
+
+func TestPeekKeepsRecency(t *testing.T) {
+	cache := NewCache(time.Minute, WithMaxEntries(2))
+	defer cache.Stop()
+
+	cache.Add("a", []byte("a"))
+	cache.Add("b", []byte("b"))
+	// Unlike Get, peeking at a leaves it the least recently used
+	cache.Peek("a")
+	cache.Add("c", []byte("c"))
+
+	if _, ok := cache.Peek("a"); ok {
+		t.Errorf("expected a to be evicted")
+	}
+}
+
+func TestMaxBytesEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewCache(time.Minute, WithMaxBytes(10))
+	defer cache.Stop()
+
+	cache.Add("a", []byte("12345"))
+	cache.Add("b", []byte("12345"))
+	cache.Add("c", []byte("12345"))
+
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("expected a to be evicted")
+	}
+	if _, ok := cache.Get("c"); !ok {
+		t.Errorf("expected c to be kept")
+	}
+
+	// A value larger than the whole cache is not stored
+	cache.Add("huge", []byte("0123456789abc"))
+	if _, ok := cache.Get("huge"); ok {
+		t.Errorf("expected oversized value to be rejected")
+	}
+	if _, ok := cache.Get("c"); !ok {
+		t.Errorf("expected oversized value not to evict c")
+	}
+}
+
+func TestStop(t *testing.T) {
+	cache := NewCache(time.Minute)
+	cache.Stop()
+	// Stopping twice must not panic
+	cache.Stop()
+
+	cache.Add("https://example.com", []byte("testdata"))
+	if _, ok := cache.Get("https://example.com"); !ok {
+		t.Errorf("expected cache to stay usable after Stop")
+	}
+}
+
+func TestKeyGroup(t *testing.T) {
+	cases := []struct {
+		key      string
+		expected string
+	}{
+		{key: "https://pokeapi.co/api/v2/pokemon/pikachu", expected: "pokemon"},
+		{key: "https://pokeapi.co/api/v2/location-area?offset=0&limit=20", expected: "location-area"},
+		{key: "http://127.0.0.1:8080/pokemon/1", expected: "pokemon"},
+		{key: "https://example.com", expected: "other"},
+	}
+
+	for _, c := range cases {
+		if actual := KeyGroup(c.key); actual != c.expected {
+			t.Errorf("KeyGroup(%q) == %q, expected %q", c.key, actual, c.expected)
+		}
+	}
+}
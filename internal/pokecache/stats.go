@@ -0,0 +1,107 @@
+// stats.go
+package pokecache
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Stats is a snapshot of cache activity since the cache was created
+type Stats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Expired   uint64 `json:"expired"`
+	Entries   int    `json:"entries"`
+	Bytes     int    `json:"bytes"`
+	// Groups breaks the totals down by KeyGroup
+	Groups map[string]GroupStats `json:"groups,omitempty"`
+}
+
+// GroupStats holds the counters for one group of keys
+type GroupStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Expired   uint64 `json:"expired"`
+	Entries   int    `json:"entries"`
+	Bytes     int    `json:"bytes"`
+}
+
+// apiVersion matches version path segments such as "v2"
+var apiVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// KeyGroup returns the resource a URL key belongs to, so
+// https://pokeapi.co/api/v2/pokemon/pikachu is grouped under "pokemon".
+// Leading "api" and version segments are skipped.
+func KeyGroup(key string) string {
+	u, err := url.Parse(key)
+	if err != nil {
+		return "other"
+	}
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment == "" || segment == "api" || apiVersion.MatchString(segment) {
+			continue
+		}
+		return segment
+	}
+	return "other"
+}
+
+// counters tracks hit/miss/eviction counts per key group
+type counters struct {
+	mutex  sync.Mutex
+	groups map[string]*GroupStats
+}
+
+func (c *counters) record(key string, update func(*GroupStats)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.groups == nil {
+		c.groups = make(map[string]*GroupStats)
+	}
+	group := KeyGroup(key)
+	stats, exists := c.groups[group]
+	if !exists {
+		stats = &GroupStats{}
+		c.groups[group] = stats
+	}
+	update(stats)
+}
+
+func (c *counters) hit(key string)    { c.record(key, func(g *GroupStats) { g.Hits++ }) }
+func (c *counters) miss(key string)   { c.record(key, func(g *GroupStats) { g.Misses++ }) }
+func (c *counters) evict(key string)  { c.record(key, func(g *GroupStats) { g.Evictions++ }) }
+func (c *counters) expire(key string) { c.record(key, func(g *GroupStats) { g.Expired++ }) }
+
+// stats builds a snapshot from the counters plus the size of each
+// currently stored entry, supplied by the cache as key -> byte size
+func (c *counters) stats(sizes map[string]int) Stats {
+	c.mutex.Lock()
+	groups := make(map[string]GroupStats, len(c.groups))
+	for name, g := range c.groups {
+		groups[name] = *g
+	}
+	c.mutex.Unlock()
+
+	for key, size := range sizes {
+		name := KeyGroup(key)
+		g := groups[name]
+		g.Entries++
+		g.Bytes += size
+		groups[name] = g
+	}
+
+	stats := Stats{Groups: groups}
+	for _, g := range groups {
+		stats.Hits += g.Hits
+		stats.Misses += g.Misses
+		stats.Evictions += g.Evictions
+		stats.Expired += g.Expired
+		stats.Entries += g.Entries
+		stats.Bytes += g.Bytes
+	}
+	return stats
+}
@@ -0,0 +1,116 @@
+// migrate.go
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// migration upgrades a decoded save document by exactly one version
+type migration func(doc map[string]json.RawMessage) error
+
+// migrations is indexed by the version a migration upgrades from.
+// migrations[n] turns a version n document into a version n+1 document.
+var migrations = []migration{
+	0: migrateV0,
+	1: migrateV1,
+	2: migrateV2,
+}
+
+// migrate upgrades doc in place to CurrentVersion
+func migrate(doc map[string]json.RawMessage) error {
+	version := 0
+	if raw, ok := doc["version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return fmt.Errorf("invalid save file version: %w", err)
+		}
+	}
+
+	if version > CurrentVersion {
+		return fmt.Errorf("save file version %d is newer than supported version %d", version, CurrentVersion)
+	}
+
+	for ; version < CurrentVersion; version++ {
+		if err := migrations[version](doc); err != nil {
+			return fmt.Errorf("migrating save file from version %d: %w", version, err)
+		}
+		doc["version"] = json.RawMessage(fmt.Sprint(version + 1))
+	}
+	return nil
+}
+
+// migrateV0 wraps an unversioned save, which held only the Pokedex map
+// keyed by Pokemon name, into the versioned layout
+func migrateV0(doc map[string]json.RawMessage) error {
+	pokedex, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	clear(doc)
+	doc["pokedex"] = pokedex
+	return nil
+}
+
+// migrateV1 renames the Go field names used by version 1 records to the
+// snake_case keys used since records gained their full details
+func migrateV1(doc map[string]json.RawMessage) error {
+	raw, ok := doc["pokedex"]
+	if !ok || string(raw) == "null" {
+		return nil
+	}
+	pokedex := map[string]map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &pokedex); err != nil {
+		return err
+	}
+	for _, record := range pokedex {
+		if name, ok := record["Name"]; ok {
+			delete(record, "Name")
+			record["name"] = name
+		}
+	}
+	migrated, err := json.Marshal(pokedex)
+	if err != nil {
+		return err
+	}
+	doc["pokedex"] = migrated
+	return nil
+}
+
+// migrateV2 replaces the single random roll used to catch Pokemon before
+// the capture formula with the odds of the catch. Old catches were made
+// with a plain Poké Ball at odds of one minus the difficulty.
+func migrateV2(doc map[string]json.RawMessage) error {
+	raw, ok := doc["pokedex"]
+	if !ok || string(raw) == "null" {
+		return nil
+	}
+	pokedex := map[string]map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &pokedex); err != nil {
+		return err
+	}
+	for _, record := range pokedex {
+		delete(record, "catch_roll")
+		rawDifficulty, ok := record["catch_difficulty"]
+		if !ok {
+			continue
+		}
+		delete(record, "catch_difficulty")
+
+		var difficulty float64
+		if err := json.Unmarshal(rawDifficulty, &difficulty); err != nil {
+			return err
+		}
+		chance, err := json.Marshal(1 - difficulty)
+		if err != nil {
+			return err
+		}
+		record["catch_chance"] = chance
+		record["ball"] = json.RawMessage(`"poke-ball"`)
+	}
+	migrated, err := json.Marshal(pokedex)
+	if err != nil {
+		return err
+	}
+	doc["pokedex"] = migrated
+	return nil
+}
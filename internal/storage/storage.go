@@ -0,0 +1,149 @@
+// storage.go
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"pokedexcli/internal/models"
+)
+
+// CurrentVersion is the schema version written by Save
+const CurrentVersion = 3
+
+// saveFileName is the name of the save file inside the config directory
+const saveFileName = "pokedex.json"
+
+// SaveFile is the on-disk representation of a REPL session
+type SaveFile struct {
+	Version  int                       `json:"version"`
+	Pokedex  map[string]models.Pokemon `json:"pokedex"`
+	Next     string                    `json:"next"`
+	Previous string                    `json:"previous"`
+	// GameVersion is the game version chosen with the version command
+	GameVersion string `json:"game_version,omitempty"`
+}
+
+// Store reads and writes the save file at a fixed path
+type Store struct {
+	path string
+}
+
+// NewStore returns a Store backed by the file at path
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultPath returns the save file location under the user's config dir
+func DefaultPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating config dir: %w", err)
+	}
+	return filepath.Join(configDir, "pokedexcli", saveFileName), nil
+}
+
+// Path returns the location of the save file
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load restores the Pokedex, pagination state and game version into config.
+// A missing save file is not an error; config is left untouched.
+func (s *Store) Load(config *models.ReplConfig) error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading save file: %w", err)
+	}
+
+	saveFile, err := decode(data)
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", s.path, err)
+	}
+
+	if saveFile.Pokedex != nil {
+		config.Pokedex = saveFile.Pokedex
+	}
+	config.Next = saveFile.Next
+	config.Previous = saveFile.Previous
+	config.Version = saveFile.GameVersion
+	return nil
+}
+
+// Save writes the Pokedex, pagination state and game version from config.
+// The file is replaced atomically so a crash never leaves a partial save.
+func (s *Store) Save(config *models.ReplConfig) error {
+	saveFile := SaveFile{
+		Version:     CurrentVersion,
+		Pokedex:     config.Pokedex,
+		Next:        config.Next,
+		Previous:    config.Previous,
+		GameVersion: config.Version,
+	}
+	data, err := json.MarshalIndent(saveFile, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding save file: %w", err)
+	}
+	return writeFileAtomic(s.path, data)
+}
+
+// decode migrates raw save data to CurrentVersion and unmarshals it
+func decode(data []byte) (SaveFile, error) {
+	doc := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return SaveFile{}, fmt.Errorf("invalid save file: %w", err)
+	}
+
+	if err := migrate(doc); err != nil {
+		return SaveFile{}, err
+	}
+
+	// Re-encode the migrated document so the typed decode sees one shape
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return SaveFile{}, err
+	}
+	saveFile := SaveFile{}
+	if err := json.Unmarshal(migrated, &saveFile); err != nil {
+		return SaveFile{}, fmt.Errorf("invalid save file: %w", err)
+	}
+	return saveFile, nil
+}
+
+// writeFileAtomic writes data to a temp file in the same directory,
+// syncs it and renames it over path
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating save dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	// Clean up the temp file on any failure; after a successful rename
+	// this is a no-op
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing save file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("syncing save file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing save file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replacing save file: %w", err)
+	}
+	return nil
+}
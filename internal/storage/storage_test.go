@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"pokedexcli/internal/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", saveFileName)
+	store := NewStore(path)
+
+	saved := &models.ReplConfig{
+		Pokedex: map[string]models.Pokemon{
+			"pikachu": {
+				ID:          25,
+				Name:        "pikachu",
+				Nickname:    "sparky",
+				Sprite:      "https://example.com/yellow/25.png",
+				Types:       []string{"electric"},
+				Stats:       []models.Stat{{Name: "speed", BaseStat: 90}},
+				Abilities:   []models.Ability{{Name: "static"}, {Name: "lightning-rod", IsHidden: true}},
+				CaughtAt:    time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+				CaughtIn:    "viridian-forest-area",
+				Level:       4,
+				Ball:        "great-ball",
+				CatchChance: 0.42,
+			},
+			"snorlax": {Name: "snorlax"},
+		},
+		Next:     "https://pokeapi.co/api/v2/location-area?offset=40&limit=20",
+		Previous: "https://pokeapi.co/api/v2/location-area?offset=0&limit=20",
+		Version:  "yellow",
+	}
+	if err := store.Save(saved); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded := &models.ReplConfig{Pokedex: map[string]models.Pokemon{}}
+	if err := store.Load(loaded); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Pokedex, saved.Pokedex) {
+		t.Errorf("expected pokedex to round trip, got %v", loaded.Pokedex)
+	}
+	if loaded.Next != saved.Next || loaded.Previous != saved.Previous {
+		t.Errorf("expected pagination to round trip, got %q / %q", loaded.Next, loaded.Previous)
+	}
+	if loaded.Version != saved.Version {
+		t.Errorf("expected game version to round trip, got %q", loaded.Version)
+	}
+
+	// No temp files should be left behind next to the save file
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the save file, found %d entries", len(entries))
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), saveFileName))
+	config := &models.ReplConfig{Pokedex: map[string]models.Pokemon{}}
+	if err := store.Load(config); err != nil {
+		t.Errorf("expected missing save file to be ignored, got %v", err)
+	}
+	if len(config.Pokedex) != 0 {
+		t.Errorf("expected empty pokedex")
+	}
+}
+
+func TestLoadMigratesOlderVersions(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "unversioned",
+			data: `{"pikachu": {"Name": "pikachu"}}`,
+		},
+		{
+			name: "version 1",
+			data: `{"version": 1, "pokedex": {"pikachu": {"Name": "pikachu"}}}`,
+		},
+		{
+			name: "version 2",
+			data: `{"version": 2, "pokedex": {"pikachu": {"name": "pikachu", "id": 25, "catch_roll": 0.9, "catch_difficulty": 0.25}}}`,
+		},
+		{
+			name: "version 3",
+			data: `{"version": 3, "pokedex": {"pikachu": {"name": "pikachu", "id": 25, "ball": "poke-ball", "catch_chance": 0.75}}}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), saveFileName)
+			if err := os.WriteFile(path, []byte(c.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			config := &models.ReplConfig{Pokedex: map[string]models.Pokemon{}}
+			if err := NewStore(path).Load(config); err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if config.Pokedex["pikachu"].Name != "pikachu" {
+				t.Errorf("expected pikachu after migration, got %v", config.Pokedex)
+			}
+			if pikachu := config.Pokedex["pikachu"]; pikachu.ID == 25 && (pikachu.Ball != "poke-ball" || pikachu.CatchChance != 0.75) {
+				t.Errorf("expected catch odds to be migrated, got %+v", pikachu)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), saveFileName)
+	if err := os.WriteFile(path, []byte(`{"version": 999, "pokedex": {}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config := &models.ReplConfig{Pokedex: map[string]models.Pokemon{}}
+	if err := NewStore(path).Load(config); err == nil {
+		t.Errorf("expected error for unsupported version")
+	}
+}
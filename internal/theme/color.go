@@ -0,0 +1,219 @@
+package theme
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Depth is how many colors a terminal can show
+type Depth int
+
+const (
+	// Basic is the 16 standard ANSI colors
+	Basic Depth = iota
+	// Ansi256 is the xterm 256-color palette
+	Ansi256
+	// TrueColor is 24-bit RGB
+	TrueColor
+)
+
+// DetectDepth works out the color depth of the terminal from COLORTERM and
+// TERM, the way most terminal programs do
+func DetectDepth(getenv func(string) string) Depth {
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+	if strings.Contains(getenv("TERM"), "256color") {
+		return Ansi256
+	}
+	return Basic
+}
+
+// basicColors are the SGR foreground codes of the named ANSI colors
+var basicColors = map[string]int{
+	"black":          30,
+	"red":            31,
+	"green":          32,
+	"yellow":         33,
+	"blue":           34,
+	"magenta":        35,
+	"purple":         35,
+	"cyan":           36,
+	"white":          37,
+	"gray":           90,
+	"grey":           90,
+	"bright-black":   90,
+	"bright-red":     91,
+	"bright-green":   92,
+	"bright-yellow":  93,
+	"bright-blue":    94,
+	"bright-magenta": 95,
+	"bright-cyan":    96,
+	"bright-white":   97,
+}
+
+// attributes are the SGR codes of text styles
+var attributes = map[string]int{
+	"bold":      1,
+	"dim":       2,
+	"italic":    3,
+	"underline": 4,
+}
+
+// basicRGB is what the 16 ANSI colors look like in xterm, in the order of
+// their 256-color indexes
+var basicRGB = [16][3]int{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the channel values of the 6x6x6 color cube
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// Escape turns a color spec into the escape sequence that shows it at
+// depth. A spec is space separated words, each one of:
+//
+//   - a named color, like red or bright-blue
+//   - a style: bold, dim, italic or underline
+//   - a 256-color index, like 208
+//   - an RGB hex color, like #ee8130
+//
+// Colors the terminal can't show are replaced by the closest one it can.
+func Escape(spec string, depth Depth) (string, error) {
+	codes := []string{}
+	for _, word := range strings.Fields(strings.ToLower(spec)) {
+		code, err := sgr(word, depth)
+		if err != nil {
+			return "", fmt.Errorf("invalid color %q: %w", spec, err)
+		}
+		codes = append(codes, code)
+	}
+	if len(codes) == 0 {
+		return "", nil
+	}
+	return "\033[" + strings.Join(codes, ";") + "m", nil
+}
+
+// sgr returns the SGR parameters for a single word of a color spec
+func sgr(word string, depth Depth) (string, error) {
+	if code, ok := basicColors[word]; ok {
+		return strconv.Itoa(code), nil
+	}
+	if code, ok := attributes[word]; ok {
+		return strconv.Itoa(code), nil
+	}
+
+	if strings.HasPrefix(word, "#") {
+		rgb, err := parseHex(word)
+		if err != nil {
+			return "", err
+		}
+		switch depth {
+		case TrueColor:
+			return fmt.Sprintf("38;2;%d;%d;%d", rgb[0], rgb[1], rgb[2]), nil
+		case Ansi256:
+			return fmt.Sprintf("38;5;%d", nearest256(rgb)), nil
+		default:
+			return basicCode(nearestBasic(rgb)), nil
+		}
+	}
+
+	index, err := strconv.Atoi(word)
+	if err != nil || index < 0 || index > 255 {
+		return "", fmt.Errorf("unknown color or style %q", word)
+	}
+	if depth == Basic {
+		return basicCode(nearestBasic(rgb256(index))), nil
+	}
+	return fmt.Sprintf("38;5;%d", index), nil
+}
+
+// parseHex reads a #rrggbb color
+func parseHex(word string) ([3]int, error) {
+	hex := strings.TrimPrefix(word, "#")
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 6 || err != nil {
+		return [3]int{}, fmt.Errorf("%q is not a #rrggbb color", word)
+	}
+	return [3]int{int(value >> 16 & 0xff), int(value >> 8 & 0xff), int(value & 0xff)}, nil
+}
+
+// rgb256 returns the RGB value of a 256-color index
+func rgb256(index int) [3]int {
+	switch {
+	case index < 16:
+		return basicRGB[index]
+	case index < 232:
+		index -= 16
+		return [3]int{cubeLevels[index/36], cubeLevels[index/6%6], cubeLevels[index%6]}
+	default:
+		level := 8 + (index-232)*10
+		return [3]int{level, level, level}
+	}
+}
+
+// nearest256 returns the 256-color index closest to rgb, from the color
+// cube or the gray ramp
+func nearest256(rgb [3]int) int {
+	cube := 16
+	for i, multiplier := range [3]int{36, 6, 1} {
+		cube += multiplier * nearestLevel(rgb[i])
+	}
+
+	average := (rgb[0] + rgb[1] + rgb[2]) / 3
+	gray := 232 + min(23, max(0, (average-3)/10))
+	if distance(rgb, rgb256(gray)) < distance(rgb, rgb256(cube)) {
+		return gray
+	}
+	return cube
+}
+
+// nearestLevel returns the index of the cube level closest to v
+func nearestLevel(v int) int {
+	best := 0
+	for i, level := range cubeLevels {
+		if abs(v-level) < abs(v-cubeLevels[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// nearestBasic returns the index of the ANSI color closest to rgb
+func nearestBasic(rgb [3]int) int {
+	best := 0
+	for i := range basicRGB {
+		if distance(rgb, basicRGB[i]) < distance(rgb, basicRGB[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// basicCode returns the SGR foreground code of an ANSI color index
+func basicCode(index int) string {
+	if index < 8 {
+		return strconv.Itoa(30 + index)
+	}
+	return strconv.Itoa(90 + index - 8)
+}
+
+// distance is the squared distance between two colors
+func distance(a, b [3]int) int {
+	sum := 0
+	for i := range a {
+		sum += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return sum
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,180 @@
+// Package theme decides whether and how output is colored: it detects
+// terminals and NO_COLOR, and loads user-defined color themes.
+package theme
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Mode is when to color output
+type Mode string
+
+const (
+	// Auto colors output written to a terminal, unless NO_COLOR is set
+	Auto   Mode = "auto"
+	Always Mode = "always"
+	Never  Mode = "never"
+)
+
+// ParseMode reads a --color value
+func ParseMode(s string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(s)); mode {
+	case Auto, Always, Never:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown color mode %q, try one of: auto, always, never", s)
+}
+
+// Enabled reports whether output should be colored. isTerminal tells
+// whether the output is a terminal rather than a pipe or a file.
+func Enabled(mode Mode, isTerminal bool, getenv func(string) string) bool {
+	switch mode {
+	case Always:
+		return true
+	case Never:
+		return false
+	}
+	// See https://no-color.org
+	if getenv("NO_COLOR") != "" || getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal
+}
+
+// Roles are the colors the CLI paints with. A theme can repaint each of
+// them, e.g. make red a softer #ff5555.
+var Roles = []string{"red", "green", "yellow", "cyan", "gray", "bold"}
+
+// Types are the 18 Pokémon types
+var Types = []string{
+	"normal", "fire", "water", "electric", "grass", "ice",
+	"fighting", "poison", "ground", "flying", "psychic", "bug",
+	"rock", "ghost", "dragon", "dark", "steel", "fairy",
+}
+
+// Theme maps roles and Pokémon types to color specs, see Escape
+type Theme struct {
+	Colors map[string]string `json:"colors"`
+	Types  map[string]string `json:"types"`
+}
+
+// Default is the standard theme. Roles use the basic ANSI colors so they
+// look right in any terminal; types use the colors of the games, which
+// are approximated on terminals without true color.
+func Default() Theme {
+	return Theme{
+		Colors: map[string]string{
+			"red":    "red",
+			"green":  "green",
+			"yellow": "yellow",
+			"cyan":   "cyan",
+			"gray":   "gray",
+			"bold":   "bold",
+		},
+		Types: map[string]string{
+			"normal":   "#a8a77a",
+			"fire":     "#ee8130",
+			"water":    "#6390f0",
+			"electric": "#f7d02c",
+			"grass":    "#7ac74c",
+			"ice":      "#96d9d6",
+			"fighting": "#c22e28",
+			"poison":   "#a33ea1",
+			"ground":   "#e2bf65",
+			"flying":   "#a98ff3",
+			"psychic":  "#f95587",
+			"bug":      "#a6b91a",
+			"rock":     "#b6a136",
+			"ghost":    "#735797",
+			"dragon":   "#6f35fc",
+			"dark":     "#705746",
+			"steel":    "#b7b7ce",
+			"fairy":    "#d685ad",
+		},
+	}
+}
+
+// DefaultPath returns where the user's theme file lives
+func DefaultPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating config dir: %w", err)
+	}
+	return filepath.Join(configDir, "pokedexcli", "theme.json"), nil
+}
+
+// Load reads the theme file at path over the default theme, so it only
+// has to list the colors it changes. A missing file is the default theme.
+func Load(path string) (Theme, error) {
+	theme := Default()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return theme, nil
+	}
+	if err != nil {
+		return theme, fmt.Errorf("reading theme: %w", err)
+	}
+
+	custom := Theme{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&custom); err != nil {
+		return theme, fmt.Errorf("reading theme %s: %w", path, err)
+	}
+	if err := merge(theme.Colors, custom.Colors, Roles); err != nil {
+		return theme, fmt.Errorf("theme %s: %w", path, err)
+	}
+	if err := merge(theme.Types, custom.Types, Types); err != nil {
+		return theme, fmt.Errorf("theme %s: %w", path, err)
+	}
+	return theme, nil
+}
+
+// merge copies the valid colors in custom over base
+func merge(base, custom map[string]string, names []string) error {
+	for name, spec := range custom {
+		if !slices.Contains(names, name) {
+			return fmt.Errorf("unknown name %q, try one of: %s", name, strings.Join(names, ", "))
+		}
+		if _, err := Escape(spec, TrueColor); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		base[name] = spec
+	}
+	return nil
+}
+
+// Palette is a theme rendered to escape sequences for one terminal. The
+// zero Palette prints no colors.
+type Palette struct {
+	Reset  string
+	Colors map[string]string
+	Types  map[string]string
+}
+
+// Palette renders the theme for a terminal with the given color depth
+func (t Theme) Palette(depth Depth) (Palette, error) {
+	palette := Palette{Reset: "\033[0m", Colors: map[string]string{}, Types: map[string]string{}}
+	for name, spec := range t.Colors {
+		escape, err := Escape(spec, depth)
+		if err != nil {
+			return Palette{}, fmt.Errorf("%s: %w", name, err)
+		}
+		palette.Colors[name] = escape
+	}
+	for name, spec := range t.Types {
+		escape, err := Escape(spec, depth)
+		if err != nil {
+			return Palette{}, fmt.Errorf("%s: %w", name, err)
+		}
+		palette.Types[name] = escape
+	}
+	return palette, nil
+}
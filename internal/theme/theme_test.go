@@ -0,0 +1,164 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// env returns a getenv backed by vars
+func env(vars map[string]string) func(string) string {
+	return func(key string) string {
+		return vars[key]
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	cases := []struct {
+		mode       Mode
+		isTerminal bool
+		env        map[string]string
+		expected   bool
+	}{
+		{mode: Auto, isTerminal: true, expected: true},
+		{mode: Auto, isTerminal: false, expected: false},
+		{mode: Auto, isTerminal: true, env: map[string]string{"NO_COLOR": "1"}, expected: false},
+		{mode: Auto, isTerminal: true, env: map[string]string{"NO_COLOR": ""}, expected: true},
+		{mode: Auto, isTerminal: true, env: map[string]string{"TERM": "dumb"}, expected: false},
+		{mode: Always, isTerminal: false, env: map[string]string{"NO_COLOR": "1"}, expected: true},
+		{mode: Never, isTerminal: true, expected: false},
+	}
+	for _, c := range cases {
+		if actual := Enabled(c.mode, c.isTerminal, env(c.env)); actual != c.expected {
+			t.Errorf("Enabled(%s, %v, %v) == %v, expected %v", c.mode, c.isTerminal, c.env, actual, c.expected)
+		}
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for _, name := range []string{"auto", "Always", "never"} {
+		if _, err := ParseMode(name); err != nil {
+			t.Errorf("ParseMode(%q): unexpected error: %v", name, err)
+		}
+	}
+	if _, err := ParseMode("sometimes"); err == nil {
+		t.Errorf("expected an error for an unknown mode")
+	}
+}
+
+func TestDetectDepth(t *testing.T) {
+	cases := []struct {
+		env      map[string]string
+		expected Depth
+	}{
+		{env: map[string]string{"COLORTERM": "truecolor", "TERM": "xterm-256color"}, expected: TrueColor},
+		{env: map[string]string{"COLORTERM": "24bit"}, expected: TrueColor},
+		{env: map[string]string{"TERM": "xterm-256color"}, expected: Ansi256},
+		{env: map[string]string{"TERM": "xterm"}, expected: Basic},
+		{expected: Basic},
+	}
+	for _, c := range cases {
+		if actual := DetectDepth(env(c.env)); actual != c.expected {
+			t.Errorf("DetectDepth(%v) == %d, expected %d", c.env, actual, c.expected)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	cases := []struct {
+		spec     string
+		depth    Depth
+		expected string
+	}{
+		{spec: "red", depth: TrueColor, expected: "\033[31m"},
+		{spec: "gray", depth: Basic, expected: "\033[90m"},
+		{spec: "bold", depth: Basic, expected: "\033[1m"},
+		{spec: "bold Cyan", depth: Basic, expected: "\033[1;36m"},
+		{spec: "#ee8130", depth: TrueColor, expected: "\033[38;2;238;129;48m"},
+		{spec: "#ee8130", depth: Ansi256, expected: "\033[38;5;209m"},
+		{spec: "#ff0000", depth: Basic, expected: "\033[91m"},
+		{spec: "#808080", depth: Ansi256, expected: "\033[38;5;244m"},
+		{spec: "208", depth: Ansi256, expected: "\033[38;5;208m"},
+		{spec: "208", depth: TrueColor, expected: "\033[38;5;208m"},
+		{spec: "21", depth: Basic, expected: "\033[34m"},
+		{spec: "", depth: TrueColor, expected: ""},
+	}
+	for _, c := range cases {
+		actual, err := Escape(c.spec, c.depth)
+		if err != nil {
+			t.Errorf("Escape(%q, %d): unexpected error: %v", c.spec, c.depth, err)
+			continue
+		}
+		if actual != c.expected {
+			t.Errorf("Escape(%q, %d) == %q, expected %q", c.spec, c.depth, actual, c.expected)
+		}
+	}
+
+	for _, spec := range []string{"pink", "#ee81", "#gggggg", "256", "-1"} {
+		if _, err := Escape(spec, TrueColor); err == nil {
+			t.Errorf("Escape(%q): expected an error", spec)
+		}
+	}
+}
+
+func TestDefaultCoversEveryType(t *testing.T) {
+	palette, err := Default().Palette(Basic)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range Types {
+		if palette.Types[name] == "" {
+			t.Errorf("no color for the %s type", name)
+		}
+	}
+	for _, name := range Roles {
+		if palette.Colors[name] == "" {
+			t.Errorf("no color for %s", name)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("writing theme: %v", err)
+		}
+		return path
+	}
+
+	// A missing file is the default theme
+	theme, err := Load(filepath.Join(dir, "missing.json"))
+	if err != nil || theme.Colors["red"] != "red" {
+		t.Fatalf("Load(missing) == %v, %v, expected the default theme", theme, err)
+	}
+
+	theme, err = Load(write("custom.json", `{"colors": {"red": "#ff5555"}, "types": {"fire": "208"}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if theme.Colors["red"] != "#ff5555" || theme.Types["fire"] != "208" {
+		t.Errorf("custom colors were not applied: %v", theme)
+	}
+	if theme.Colors["green"] != "green" || theme.Types["water"] != "#6390f0" {
+		t.Errorf("colors the file doesn't list should keep their default: %v", theme)
+	}
+
+	cases := []struct {
+		content string
+		errText string
+	}{
+		{content: `{"colors": {"red": "pink"}}`, errText: "unknown color"},
+		{content: `{"types": {"sound": "red"}}`, errText: "unknown name"},
+		{content: `{"colours": {}}`, errText: "unknown field"},
+		{content: `{`, errText: "reading theme"},
+	}
+	for _, c := range cases {
+		_, err := Load(write("broken.json", c.content))
+		if err == nil || !strings.Contains(err.Error(), c.errText) {
+			t.Errorf("Load(%s) == %v, expected an error containing %q", c.content, err, c.errText)
+		}
+	}
+}